@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"crawler/internal/proxy"
+	"crawler/internal/repository"
+	"crawler/internal/service"
+	"crawler/pkg/config"
+	"crawler/pkg/logger"
+	"crawler/pkg/mysql"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+)
+
+// 将已保存的文章导出为 Markdown，与 POST /api/export/markdown 相同
+func main() {
+	configPath := flag.String("config", "config.yaml", "配置文件路径")
+	ids := flag.String("ids", "", "要导出的文章ID，逗号分隔，为空时导出全部")
+	tags := flag.String("tags", "", "追加到 front matter 的标签，逗号分隔")
+	includeDeleted := flag.Bool("include-deleted", false, "包含已删除的文章")
+	skipImages := flag.Bool("skip-images", false, "不下载图片，保留知乎图片地址")
+	flag.Parse()
+
+	opts := service.ExportOptions{
+		Tags:           splitList(*tags),
+		IncludeDeleted: *includeDeleted,
+		SkipImages:     *skipImages,
+		Keep:           true,
+	}
+	for _, raw := range splitList(*ids) {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			log.Fatalf("无效的文章ID: %s", raw)
+		}
+		opts.IDs = append(opts.IDs, id)
+	}
+
+	// 1. 加载配置
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		log.Fatalf("配置初始化失败: %v", err)
+	}
+
+	// 2. 初始化日志系统
+	if err := logger.InitializeLogger(cfg.Logger); err != nil {
+		log.Fatalf("日志系统初始化失败: %v", err)
+	}
+
+	// 3. 初始化数据库连接
+	db, err := mysql.NewDB(cfg.MySQL)
+	if err != nil {
+		logger.Fatal("数据库连接失败", "error", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	proxies := proxy.NewPool(cfg.Proxy)
+	defer proxies.Close()
+
+	exportService := service.NewExportService(cfg, proxies,
+		repository.NewGormArticleRepository(db),
+		repository.NewGormArticleRevisionRepository(db),
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := exportService.ExportMarkdown(ctx, opts)
+	if err != nil {
+		logger.Error("导出失败", "error", err)
+		os.Exit(1)
+	}
+	fmt.Printf("导出 %d 篇文章，%d 张图片（失败 %d 张）\n目录: %s\n压缩包: %s\n",
+		result.Articles, result.Images, result.FailedImages, result.Dir, result.ZipPath)
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
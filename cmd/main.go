@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"crawler/internal/di"
 	"crawler/pkg/config"
 	"crawler/pkg/logger"
 	"crawler/pkg/mysql"
+	"crawler/pkg/tracing"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"time"
 )
 
 func main() {
@@ -21,13 +26,34 @@ func main() {
 		log.Fatalf("日志系统初始化失败: %v", err)
 	}
 
-	// 3. 初始化数据库连接
+	// 启动失败时在资源清理完成后再以非 0 状态退出
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	// 3. 初始化链路追踪
+	shutdownTracing, err := tracing.Init(cfg.Tracing)
+	if err != nil {
+		logger.Fatal("链路追踪初始化失败", "error", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.Error("链路追踪关闭失败", "error", err)
+		}
+	}()
+
+	// 4. 初始化数据库连接
 	db, err := mysql.NewDB(cfg.MySQL)
 	if err != nil {
 		logger.Fatal("数据库连接失败", "error", err)
 	}
 
-	// 4. 初始化依赖注入容器
+	// 5. 初始化依赖注入容器
 	container, err := di.NewContainer(cfg, db)
 	if err != nil {
 		logger.Fatal("依赖注入容器初始化失败", "error", err)
@@ -36,10 +62,10 @@ func main() {
 	// 确保资源正确清理
 	defer container.ReleaseResources()
 
-	// 6. 启动服务
+	// 6. 启动服务，收到退出信号后正常返回，执行上面的资源清理
 	logger.Info("开始启动服务", "port", cfg.Server.Port)
-	if err := container.Router.ServeHTTP(cfg.Server.Port); err != nil {
-		logger.Fatal("服务启动失败", "error", err)
-		os.Exit(1)
+	if err := container.Router.ServeHTTP(cfg.Server.Port); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("服务启动失败", "error", err)
+		exitCode = 1
 	}
 }
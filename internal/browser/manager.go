@@ -0,0 +1,265 @@
+package browser
+
+import (
+	"context"
+	"crawler/internal/progress"
+	"crawler/internal/proxy"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/metrics"
+	"crawler/pkg/tracing"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrManagerClosed 浏览器管理器已关闭
+var ErrManagerClosed = errcode.New(errcode.BrowserLaunchFailed, "浏览器管理器已关闭")
+
+// 浏览器重启原因
+const (
+	restartCrashed = "crashed"
+	restartMaxUses = "max_uses"
+)
+
+// Manager 长期持有的浏览器，为每个任务分配独立的上下文
+type Manager interface {
+	// Acquire 获取一个独立的浏览器上下文，超过并发上限时等待，px 不为 nil 时上下文使用该代理
+	Acquire(ctx context.Context, px *proxy.Proxy) (*Session, error)
+	// Close 关闭浏览器和 Playwright
+	Close()
+}
+
+// Session 任务独占的浏览器上下文，用完后必须调用 Close
+type Session struct {
+	Context playwright.BrowserContext
+
+	once    sync.Once
+	release func()
+}
+
+// Close 关闭上下文并归还并发名额
+func (s *Session) Close() {
+	s.once.Do(func() {
+		s.Context.Close()
+		s.release()
+	})
+}
+
+// instance 一次启动的浏览器进程
+type instance struct {
+	browser playwright.Browser
+	uses    int  // 已分配的上下文数
+	active  int  // 未关闭的上下文数
+	retired bool // 已被替换，最后一个上下文关闭后退出
+}
+
+type manager struct {
+	config config.BrowserConfig
+	log    logger.Logger
+
+	mu      sync.Mutex
+	pw      *playwright.Playwright
+	current *instance
+	retired []*instance
+	closed  bool
+
+	slots chan struct{}
+}
+
+func NewManager(cfg config.BrowserConfig) Manager {
+	return &manager{
+		config: cfg,
+		log:    logger.FromContext(context.Background()).WithModule(logger.ModuleBrowser),
+		slots:  make(chan struct{}, cfg.ContextLimit()),
+	}
+}
+
+func (m *manager) Acquire(ctx context.Context, px *proxy.Proxy) (*Session, error) {
+	log := logger.FromContext(ctx).WithModule(logger.ModuleBrowser)
+
+	select {
+	case m.slots <- struct{}{}:
+	default:
+		log.Info("浏览器上下文已达上限，等待空闲", "limit", cap(m.slots))
+		select {
+		case m.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	inst, err := m.instance(ctx)
+	if err != nil {
+		<-m.slots
+		return nil, err
+	}
+
+	browserCtx, err := inst.browser.NewContext(contextOptions(m.config, px))
+	if err != nil {
+		m.release(inst)
+		return nil, errcode.Wrap(errcode.BrowserLaunchFailed, "failed to create browser context", err)
+	}
+	applyTimeouts(browserCtx, m.config)
+	metrics.BrowserContextsActive.Inc()
+
+	return &Session{
+		Context: browserCtx,
+		release: func() {
+			metrics.BrowserContextsActive.Dec()
+			m.release(inst)
+		},
+	}, nil
+}
+
+// instance 返回可用的浏览器，浏览器崩溃或使用次数达到上限时重新启动
+func (m *manager) instance(ctx context.Context) (*instance, error) {
+	log := logger.FromContext(ctx).WithModule(logger.ModuleBrowser)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+
+	reused := true
+	if m.current != nil {
+		reason := ""
+		if !m.current.browser.IsConnected() {
+			reason = restartCrashed
+		} else if maxUses := m.config.MaxUses; maxUses > 0 && m.current.uses >= maxUses {
+			reason = restartMaxUses
+		}
+		if reason != "" {
+			log.Warn("重启浏览器", "reason", reason, "uses", m.current.uses)
+			metrics.BrowserRestartsTotal.WithLabelValues(reason).Inc()
+			m.retire(m.current)
+			m.current = nil
+		}
+	}
+	if m.current == nil {
+		browser, err := m.launch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		m.current = &instance{browser: browser}
+		reused = false
+	}
+
+	m.current.uses++
+	m.current.active++
+	progress.Report(ctx, progress.EventBrowserLaunched, map[string]interface{}{
+		"engine": m.config.EngineName(),
+		"reused": reused,
+	})
+	return m.current, nil
+}
+
+// launch 启动 Playwright（首次或上次失败后）和浏览器，调用方需持有锁
+func (m *manager) launch(ctx context.Context) (_ playwright.Browser, err error) {
+	ctx, span := tracing.Start(ctx, "browser.launch",
+		attribute.String("browser.engine", m.config.EngineName()),
+	)
+	defer func() { tracing.End(span, err) }()
+	log := logger.FromContext(ctx).WithModule(logger.ModuleBrowser)
+
+	start := time.Now()
+	defer func() {
+		metrics.BrowserLaunchDuration.WithLabelValues(metrics.Outcome(err)).Observe(time.Since(start).Seconds())
+	}()
+
+	if m.pw == nil {
+		log.Info("初始化 Playwright")
+		pw, err := playwright.Run()
+		if err != nil {
+			log.Error("Playwright 安装失败", "error", err)
+			return nil, errcode.Wrap(errcode.BrowserLaunchFailed, "playwright installation failed", err)
+		}
+		m.pw = pw
+	}
+
+	opts := launchOptions(m.config)
+	browser, err := browserType(m.pw, m.config).Launch(opts)
+	if err != nil {
+		log.Error("浏览器启动失败",
+			"error", err,
+			"engine", m.config.EngineName(),
+			"options", opts,
+			"duration", time.Since(start).String(),
+		)
+		// 驱动可能已经异常，下次重新启动
+		m.pw.Stop()
+		m.pw = nil
+		return nil, errcode.Wrap(errcode.BrowserLaunchFailed, "browser launch failed", err)
+	}
+
+	log.Info("浏览器启动完成",
+		"engine", m.config.EngineName(),
+		"headless", m.config.Headless,
+		"duration", time.Since(start).String(),
+	)
+	return browser, nil
+}
+
+// release 上下文关闭后更新计数，已替换的浏览器在最后一个上下文关闭后退出
+func (m *manager) release(inst *instance) {
+	m.mu.Lock()
+	inst.active--
+	if inst.retired && inst.active == 0 {
+		m.closeInstance(inst)
+	}
+	m.mu.Unlock()
+	<-m.slots
+}
+
+// retire 替换浏览器，仍有上下文在使用时延后关闭，调用方需持有锁
+func (m *manager) retire(inst *instance) {
+	inst.retired = true
+	if inst.active == 0 {
+		m.closeInstance(inst)
+		return
+	}
+	m.retired = append(m.retired, inst)
+}
+
+// closeInstance 关闭浏览器进程，调用方需持有锁
+func (m *manager) closeInstance(inst *instance) {
+	if err := inst.browser.Close(); err != nil && !errors.Is(err, playwright.ErrTargetClosed) {
+		m.log.Warn("关闭浏览器失败", "error", err)
+	}
+	for i, r := range m.retired {
+		if r == inst {
+			m.retired = append(m.retired[:i], m.retired[i+1:]...)
+			break
+		}
+	}
+}
+
+func (m *manager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return
+	}
+	m.closed = true
+	m.log.Info("关闭浏览器")
+
+	for _, inst := range m.retired {
+		inst.browser.Close()
+	}
+	m.retired = nil
+	if m.current != nil {
+		m.current.browser.Close()
+		m.current = nil
+	}
+	if m.pw != nil {
+		m.pw.Stop()
+		m.pw = nil
+	}
+}
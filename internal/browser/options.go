@@ -0,0 +1,73 @@
+package browser
+
+import (
+	"crawler/internal/proxy"
+	"crawler/pkg/config"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// browserType 根据配置选择浏览器内核
+func browserType(pw *playwright.Playwright, cfg config.BrowserConfig) playwright.BrowserType {
+	switch cfg.EngineName() {
+	case config.BrowserFirefox:
+		return pw.Firefox
+	case config.BrowserWebKit:
+		return pw.WebKit
+	default:
+		return pw.Chromium
+	}
+}
+
+// launchOptions 浏览器启动参数
+func launchOptions(cfg config.BrowserConfig) playwright.BrowserTypeLaunchOptions {
+	opts := playwright.BrowserTypeLaunchOptions{
+		Headless: playwright.Bool(cfg.Headless),
+		Args:     cfg.Args,
+	}
+	if cfg.ExecutablePath != "" {
+		opts.ExecutablePath = playwright.String(cfg.ExecutablePath)
+	}
+	if cfg.SlowMo > 0 {
+		opts.SlowMo = playwright.Float(float64(cfg.SlowMo.Milliseconds()))
+	}
+	return opts
+}
+
+// contextOptions 浏览器上下文参数，px 不为 nil 时上下文内的请求都经过该代理
+func contextOptions(cfg config.BrowserConfig, px *proxy.Proxy) playwright.BrowserNewContextOptions {
+	var opts playwright.BrowserNewContextOptions
+	if px != nil {
+		opts.Proxy = &playwright.Proxy{Server: px.Server}
+		if px.Username != "" {
+			opts.Proxy.Username = playwright.String(px.Username)
+			opts.Proxy.Password = playwright.String(px.Password)
+		}
+	}
+	if cfg.Viewport.Width > 0 {
+		opts.Viewport = &playwright.Size{
+			Width:  cfg.Viewport.Width,
+			Height: cfg.Viewport.Height,
+		}
+	}
+	if cfg.Locale != "" {
+		opts.Locale = playwright.String(cfg.Locale)
+	}
+	if cfg.Timezone != "" {
+		opts.TimezoneId = playwright.String(cfg.Timezone)
+	}
+	if cfg.UserAgent != "" {
+		opts.UserAgent = playwright.String(cfg.UserAgent)
+	}
+	return opts
+}
+
+// applyTimeouts 设置上下文内页面的默认超时时间
+func applyTimeouts(browserCtx playwright.BrowserContext, cfg config.BrowserConfig) {
+	if cfg.Timeout > 0 {
+		browserCtx.SetDefaultTimeout(float64(cfg.Timeout.Milliseconds()))
+	}
+	if cfg.NavigationTimeout > 0 {
+		browserCtx.SetDefaultNavigationTimeout(float64(cfg.NavigationTimeout.Milliseconds()))
+	}
+}
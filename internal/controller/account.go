@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"crawler/internal/repository"
+	"crawler/internal/service"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/response"
+	"crawler/pkg/timezone"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IAccountController 账号数据控制器接口
+type IAccountController interface {
+	HandleMetrics(c *gin.Context)
+	HandleDaily(c *gin.Context)
+}
+
+type AccountController struct {
+	accountService service.IAccountService
+}
+
+func NewAccountController(service service.IAccountService) IAccountController {
+	return &AccountController{
+		accountService: service,
+	}
+}
+
+// accountMetricsView 对外展示的账号数据快照
+type accountMetricsView struct {
+	CapturedAt     time.Time `json:"captured_at"`
+	CrawlRunID     string    `json:"crawl_run_id"`
+	Followers      int       `json:"followers"`
+	TotalReads     int       `json:"total_reads"`
+	TotalUpvotes   int       `json:"total_upvotes"`
+	DataDate       string    `json:"data_date"`
+	DailyReads     int       `json:"daily_reads"`
+	DailyUpvotes   int       `json:"daily_upvotes"`
+	DailyFollowers int       `json:"daily_followers"`
+}
+
+func newAccountMetricsView(s repository.AccountMetricsSnapshot) accountMetricsView {
+	return accountMetricsView{
+		CapturedAt:     s.CapturedAt,
+		CrawlRunID:     s.CrawlRunID,
+		Followers:      s.Followers,
+		TotalReads:     s.TotalReads,
+		TotalUpvotes:   s.TotalUpvotes,
+		DataDate:       s.DataDate.In(timezone.Shanghai).Format(time.DateOnly),
+		DailyReads:     s.DailyReads,
+		DailyUpvotes:   s.DailyUpvotes,
+		DailyFollowers: s.DailyFollowers,
+	}
+}
+
+// accountDailyView 对外展示的账号每日数据
+type accountDailyView struct {
+	Date      string `json:"date"`
+	Reads     int    `json:"reads"`
+	Upvotes   int    `json:"upvotes"`
+	Followers int    `json:"followers"`
+}
+
+func newAccountDailyView(m repository.AccountDailyMetric) accountDailyView {
+	return accountDailyView{
+		Date:      m.Date.In(timezone.Shanghai).Format(time.DateOnly),
+		Reads:     m.Reads,
+		Upvotes:   m.Upvotes,
+		Followers: m.Followers,
+	}
+}
+
+// parseTimeQuery 解析可选的时间查询参数，支持 RFC3339 和日期（2006-01-02，按 Asia/Shanghai 时区）。
+// endOfDay 为 true 时日期表示包含当天，返回次日零点
+func parseTimeQuery(c *gin.Context, name string, endOfDay bool) (time.Time, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return time.Time{}, true
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, true
+	}
+	t, err := time.ParseInLocation(time.DateOnly, raw, timezone.Shanghai)
+	if err != nil {
+		response.FromError(c, errcode.New(errcode.InvalidRequest, "无效的参数 "+name+"，格式为 2006-01-02 或 RFC3339"))
+		return time.Time{}, false
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, true
+}
+
+// HandleMetrics 返回账号在时间范围内的数据快照，to 为日期时包含当天
+func (ac *AccountController) HandleMetrics(c *gin.Context) {
+	from, ok := parseTimeQuery(c, "from", false)
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to", true)
+	if !ok {
+		return
+	}
+
+	account := c.Param("name")
+	snapshots, err := ac.accountService.Metrics(c.Request.Context(), account, from, to)
+	if err != nil {
+		if errcode.Of(err) != errcode.InvalidRequest {
+			logger.FromContext(c.Request.Context()).Error("查询账号数据失败",
+				"error", err,
+				"account", account,
+			)
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	views := make([]accountMetricsView, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		views = append(views, newAccountMetricsView(snapshot))
+	}
+	response.Success(c, "查询成功", views)
+}
+
+// HandleDaily 返回账号在日期范围内的每日数据，to 为日期时包含当天
+func (ac *AccountController) HandleDaily(c *gin.Context) {
+	from, ok := parseTimeQuery(c, "from", false)
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to", true)
+	if !ok {
+		return
+	}
+
+	account := c.Param("name")
+	metrics, err := ac.accountService.Daily(c.Request.Context(), account, from, to)
+	if err != nil {
+		if errcode.Of(err) != errcode.InvalidRequest {
+			logger.FromContext(c.Request.Context()).Error("查询账号每日数据失败",
+				"error", err,
+				"account", account,
+			)
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	views := make([]accountDailyView, 0, len(metrics))
+	for _, m := range metrics {
+		views = append(views, newAccountDailyView(m))
+	}
+	response.Success(c, "查询成功", views)
+}
@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IAdminController 运维管理控制器接口
+type IAdminController interface {
+	HandleGetLogLevel(c *gin.Context)
+	HandleSetLogLevel(c *gin.Context)
+}
+
+type AdminController struct{}
+
+func NewAdminController() IAdminController {
+	return &AdminController{}
+}
+
+type setLogLevelRequest struct {
+	Module string `json:"module"` // 为空时修改全局级别
+	Level  string `json:"level"`  // 指定模块时为空表示恢复跟随全局级别
+}
+
+func (ac *AdminController) HandleGetLogLevel(c *gin.Context) {
+	response.Success(c, "查询成功", gin.H{
+		"levels":  logger.GetLevels(),
+		"modules": logger.Modules(),
+	})
+}
+
+func (ac *AdminController) HandleSetLogLevel(c *gin.Context) {
+	var req setLogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FromError(c, errcode.Wrap(errcode.InvalidRequest, "请求参数错误", err))
+		return
+	}
+	if req.Module == "" && req.Level == "" {
+		response.FromError(c, errcode.New(errcode.InvalidRequest, "日志级别不能为空"))
+		return
+	}
+
+	if err := logger.SetLevel(req.Module, req.Level); err != nil {
+		response.FromError(c, errcode.New(errcode.InvalidRequest, err.Error()))
+		return
+	}
+
+	logger.FromContext(c.Request.Context()).Warn("日志级别已修改",
+		"module", req.Module,
+		"level", req.Level,
+	)
+	response.Success(c, "修改成功", logger.GetLevels())
+}
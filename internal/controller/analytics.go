@@ -0,0 +1,238 @@
+package controller
+
+import (
+	"crawler/internal/repository"
+	"crawler/internal/service"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/response"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IAnalyticsController 文章数据分析控制器接口
+type IAnalyticsController interface {
+	HandleTop(c *gin.Context)
+	HandleDaily(c *gin.Context)
+	HandleEngagement(c *gin.Context)
+	HandleCadence(c *gin.Context)
+}
+
+type AnalyticsController struct {
+	analyticsService service.IAnalyticsService
+}
+
+func NewAnalyticsController(service service.IAnalyticsService) IAnalyticsController {
+	return &AnalyticsController{
+		analyticsService: service,
+	}
+}
+
+type rankedArticleView struct {
+	ID          int64      `json:"id"`
+	Title       string     `json:"title"`
+	Link        string     `json:"link"`
+	PublishedAt *time.Time `json:"published_at"`
+	Value       int        `json:"value"`
+}
+
+type dailyStatsView struct {
+	Date           string `json:"date"`
+	Reads          int    `json:"reads"`
+	Upvotes        int    `json:"upvotes"`
+	Comments       int    `json:"comments"`
+	Bookmarks      int    `json:"bookmarks"`
+	Likes          int    `json:"likes"`
+	ReadsDelta     *int   `json:"reads_delta"`
+	UpvotesDelta   *int   `json:"upvotes_delta"`
+	CommentsDelta  *int   `json:"comments_delta"`
+	BookmarksDelta *int   `json:"bookmarks_delta"`
+	LikesDelta     *int   `json:"likes_delta"`
+}
+
+type engagementView struct {
+	ID           int64    `json:"id,omitempty"`
+	Title        string   `json:"title,omitempty"`
+	Link         string   `json:"link,omitempty"`
+	Reads        int      `json:"reads"`
+	Upvotes      int      `json:"upvotes"`
+	Bookmarks    int      `json:"bookmarks"`
+	UpvoteRate   *float64 `json:"upvote_rate"`
+	BookmarkRate *float64 `json:"bookmark_rate"`
+}
+
+func newEngagementView(e repository.Engagement) engagementView {
+	return engagementView{
+		ID:           e.ID,
+		Title:        e.Title,
+		Link:         e.Link,
+		Reads:        e.Reads,
+		Upvotes:      e.Upvotes,
+		Bookmarks:    e.Bookmarks,
+		UpvoteRate:   e.UpvoteRate,
+		BookmarkRate: e.BookmarkRate,
+	}
+}
+
+type cadenceView struct {
+	Month    string `json:"month"`
+	Articles int    `json:"articles"`
+	Reads    int    `json:"reads"`
+	Upvotes  int    `json:"upvotes"`
+}
+
+// queryInt 解析可选的整数查询参数，未传时为 0
+func queryInt(c *gin.Context, name string) (int, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, true
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		response.FromError(c, errcode.New(errcode.InvalidRequest, "无效的参数 "+name))
+		return 0, false
+	}
+	return n, true
+}
+
+// handleAnalyticsError 记录非参数错误后返回错误响应
+func handleAnalyticsError(c *gin.Context, msg string, err error) {
+	if errcode.Of(err) != errcode.InvalidRequest {
+		logger.FromContext(c.Request.Context()).Error(msg,
+			"error", err,
+		)
+	}
+	response.FromError(c, err)
+}
+
+// HandleTop 按统计项返回排名靠前的文章，指定 from/to 时按时间窗口内的增量排序
+func (ac *AnalyticsController) HandleTop(c *gin.Context) {
+	from, ok := parseTimeQuery(c, "from", false)
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to", false)
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+
+	articles, err := ac.analyticsService.TopArticles(c.Request.Context(), c.Query("stat"), from, to, limit)
+	if err != nil {
+		handleAnalyticsError(c, "查询文章排行失败", err)
+		return
+	}
+
+	views := make([]rankedArticleView, 0, len(articles))
+	for _, a := range articles {
+		views = append(views, rankedArticleView{
+			ID:          a.ID,
+			Title:       a.Title,
+			Link:        a.Link,
+			PublishedAt: a.PublishedAt,
+			Value:       a.Value,
+		})
+	}
+	response.Success(c, "查询成功", views)
+}
+
+// HandleDaily 返回每日统计数据合计和与上一个快照日相比的变化，可用 article_id 只统计一篇文章
+func (ac *AnalyticsController) HandleDaily(c *gin.Context) {
+	from, ok := parseTimeQuery(c, "from", false)
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to", false)
+	if !ok {
+		return
+	}
+	articleID, ok := queryID(c, "article_id")
+	if !ok {
+		return
+	}
+
+	stats, err := ac.analyticsService.DailyStats(c.Request.Context(), articleID, from, to)
+	if err != nil {
+		handleAnalyticsError(c, "查询每日统计失败", err)
+		return
+	}
+
+	views := make([]dailyStatsView, 0, len(stats))
+	for _, s := range stats {
+		views = append(views, dailyStatsView{
+			Date:           s.SnapshotDate.Format(time.DateOnly),
+			Reads:          s.Reads,
+			Upvotes:        s.Upvotes,
+			Comments:       s.Comments,
+			Bookmarks:      s.Bookmarks,
+			Likes:          s.Likes,
+			ReadsDelta:     s.ReadsDelta,
+			UpvotesDelta:   s.UpvotesDelta,
+			CommentsDelta:  s.CommentsDelta,
+			BookmarksDelta: s.BookmarksDelta,
+			LikesDelta:     s.LikesDelta,
+		})
+	}
+	response.Success(c, "查询成功", views)
+}
+
+// HandleEngagement 返回点赞率、收藏率排行和整体互动率
+func (ac *AnalyticsController) HandleEngagement(c *gin.Context) {
+	minReads, ok := queryInt(c, "min_reads")
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(c, "limit")
+	if !ok {
+		return
+	}
+
+	report, err := ac.analyticsService.Engagement(c.Request.Context(), c.Query("order"), minReads, limit)
+	if err != nil {
+		handleAnalyticsError(c, "查询互动率失败", err)
+		return
+	}
+
+	articles := make([]engagementView, 0, len(report.Articles))
+	for _, e := range report.Articles {
+		articles = append(articles, newEngagementView(e))
+	}
+	response.Success(c, "查询成功", gin.H{
+		"summary":  newEngagementView(*report.Summary),
+		"articles": articles,
+	})
+}
+
+// HandleCadence 返回每月发布的文章数，to 为日期时包含当天
+func (ac *AnalyticsController) HandleCadence(c *gin.Context) {
+	from, ok := parseTimeQuery(c, "from", false)
+	if !ok {
+		return
+	}
+	to, ok := parseTimeQuery(c, "to", true)
+	if !ok {
+		return
+	}
+
+	months, err := ac.analyticsService.PublishingCadence(c.Request.Context(), from, to)
+	if err != nil {
+		handleAnalyticsError(c, "查询发布节奏失败", err)
+		return
+	}
+
+	views := make([]cadenceView, 0, len(months))
+	for _, m := range months {
+		views = append(views, cadenceView{
+			Month:    m.Month,
+			Articles: m.Articles,
+			Reads:    m.Reads,
+			Upvotes:  m.Upvotes,
+		})
+	}
+	response.Success(c, "查询成功", views)
+}
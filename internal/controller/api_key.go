@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"crawler/internal/repository"
+	"crawler/internal/service"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/response"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IAPIKeyController API 密钥管理控制器接口
+type IAPIKeyController interface {
+	HandleCreate(c *gin.Context)
+	HandleList(c *gin.Context)
+	HandleDelete(c *gin.Context)
+}
+
+type APIKeyController struct {
+	keyService service.IAPIKeyService
+}
+
+func NewAPIKeyController(service service.IAPIKeyService) IAPIKeyController {
+	return &APIKeyController{
+		keyService: service,
+	}
+}
+
+type createAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// apiKeyView 对外展示的密钥信息，不包含哈希
+type apiKeyView struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func newAPIKeyView(key repository.APIKey) apiKeyView {
+	return apiKeyView{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     strings.Split(key.Scopes, ","),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func (kc *APIKeyController) HandleCreate(c *gin.Context) {
+	var req createAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.FromError(c, errcode.Wrap(errcode.InvalidRequest, "请求参数错误", err))
+		return
+	}
+
+	rawKey, key, err := kc.keyService.CreateKey(c.Request.Context(), req.Name, req.Scopes, req.ExpiresAt)
+	if err != nil {
+		if errcode.Of(err) != errcode.InvalidRequest {
+			logger.FromContext(c.Request.Context()).Error("创建密钥失败",
+				"error", err,
+			)
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	// 明文密钥只返回这一次
+	response.Success(c, "创建成功", gin.H{
+		"key":     rawKey,
+		"details": newAPIKeyView(*key),
+	})
+}
+
+func (kc *APIKeyController) HandleList(c *gin.Context) {
+	keys, err := kc.keyService.ListKeys(c.Request.Context())
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("查询密钥失败",
+			"error", err,
+		)
+		response.FromError(c, err)
+		return
+	}
+
+	views := make([]apiKeyView, 0, len(keys))
+	for _, key := range keys {
+		views = append(views, newAPIKeyView(key))
+	}
+	response.Success(c, "查询成功", views)
+}
+
+func (kc *APIKeyController) HandleDelete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.FromError(c, errcode.New(errcode.InvalidRequest, "无效的密钥ID"))
+		return
+	}
+
+	deleted, err := kc.keyService.DeleteKey(c.Request.Context(), id)
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Error("删除密钥失败",
+			"error", err,
+			"key_id", id,
+		)
+		response.FromError(c, err)
+		return
+	}
+	if !deleted {
+		response.FromError(c, errcode.New(errcode.NotFound, "密钥不存在"))
+		return
+	}
+
+	response.Success(c, "删除成功", nil)
+}
@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"crawler/internal/repository"
+	"crawler/internal/service"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/response"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IArticleController 文章数据控制器接口
+type IArticleController interface {
+	HandleRevisions(c *gin.Context)
+	HandleRevisionDiff(c *gin.Context)
+	HandleComments(c *gin.Context)
+}
+
+type ArticleController struct {
+	articleService service.IArticleService
+}
+
+func NewArticleController(service service.IArticleService) IArticleController {
+	return &ArticleController{
+		articleService: service,
+	}
+}
+
+// revisionView 对外展示的正文版本信息，不包含正文
+type revisionView struct {
+	ID          int64     `json:"id"`
+	ContentHash string    `json:"content_hash"`
+	Title       string    `json:"title"`
+	CrawlRunID  string    `json:"crawl_run_id"`
+	LastRunID   string    `json:"last_run_id"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func newRevisionView(r repository.ArticleRevision) revisionView {
+	return revisionView{
+		ID:          r.ID,
+		ContentHash: r.ContentHash,
+		Title:       r.Title,
+		CrawlRunID:  r.CrawlRunID,
+		LastRunID:   r.LastRunID,
+		LastSeenAt:  r.LastSeenAt,
+		CreatedAt:   r.CreatedAt,
+	}
+}
+
+// commentView 对外展示的评论，一级评论的回复放在 replies 中
+type commentView struct {
+	ID          string        `json:"id"`
+	ParentID    string        `json:"parent_id,omitempty"`
+	AuthorName  string        `json:"author_name"`
+	AuthorToken string        `json:"author_token,omitempty"`
+	Content     string        `json:"content"`
+	Likes       int           `json:"likes"`
+	ReplyCount  int           `json:"reply_count"`
+	CommentedAt time.Time     `json:"commented_at"`
+	Replies     []commentView `json:"replies,omitempty"`
+}
+
+func newCommentView(c repository.Comment) commentView {
+	return commentView{
+		ID:          c.CommentID,
+		ParentID:    c.ParentCommentID,
+		AuthorName:  c.AuthorName,
+		AuthorToken: c.AuthorToken,
+		Content:     c.Content,
+		Likes:       c.Likes,
+		ReplyCount:  c.ReplyCount,
+		CommentedAt: c.CommentedAt,
+	}
+}
+
+// commentThreads 将评论按一级评论分组，一级评论没有保存下来的回复单独列出
+func commentThreads(comments []repository.Comment) []commentView {
+	threads := make([]commentView, 0)
+	index := make(map[string]int)
+	for _, comment := range comments {
+		if comment.RootCommentID == "" {
+			index[comment.CommentID] = len(threads)
+			threads = append(threads, newCommentView(comment))
+		}
+	}
+	for _, comment := range comments {
+		if comment.RootCommentID == "" {
+			continue
+		}
+		if i, ok := index[comment.RootCommentID]; ok {
+			threads[i].Replies = append(threads[i].Replies, newCommentView(comment))
+		} else {
+			threads = append(threads, newCommentView(comment))
+		}
+	}
+	return threads
+}
+
+// articleID 解析路径中的文章ID，无效时返回 false 并写入错误响应
+func articleID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.FromError(c, errcode.New(errcode.InvalidRequest, "无效的文章ID"))
+		return 0, false
+	}
+	return id, true
+}
+
+// queryID 解析可选的ID查询参数，未传时为 0
+func queryID(c *gin.Context, name string) (int64, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || id <= 0 {
+		response.FromError(c, errcode.New(errcode.InvalidRequest, "无效的参数 "+name))
+		return 0, false
+	}
+	return id, true
+}
+
+// handleArticleError 记录非预期错误后返回错误响应
+func handleArticleError(c *gin.Context, msg string, err error) {
+	if errcode.Of(err) != errcode.NotFound {
+		logger.FromContext(c.Request.Context()).Error(msg,
+			"error", err,
+			"article_id", c.Param("id"),
+		)
+	}
+	response.FromError(c, err)
+}
+
+// HandleRevisions 按时间顺序返回文章的正文版本
+func (ac *ArticleController) HandleRevisions(c *gin.Context) {
+	id, ok := articleID(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := ac.articleService.ListRevisions(c.Request.Context(), id)
+	if err != nil {
+		handleArticleError(c, "查询正文版本失败", err)
+		return
+	}
+
+	views := make([]revisionView, 0, len(revisions))
+	for _, revision := range revisions {
+		views = append(views, newRevisionView(revision))
+	}
+	response.Success(c, "查询成功", views)
+}
+
+// HandleRevisionDiff 返回两个正文版本的 unified diff 纯文本，
+// 默认比较最新版本和上一个版本
+func (ac *ArticleController) HandleRevisionDiff(c *gin.Context) {
+	id, ok := articleID(c)
+	if !ok {
+		return
+	}
+	from, ok := queryID(c, "from")
+	if !ok {
+		return
+	}
+	to, ok := queryID(c, "to")
+	if !ok {
+		return
+	}
+
+	diff, err := ac.articleService.DiffRevisions(c.Request.Context(), id, from, to)
+	if err != nil {
+		handleArticleError(c, "比较正文版本失败", err)
+		return
+	}
+	c.String(http.StatusOK, diff)
+}
+
+// HandleComments 返回文章的评论，按一级评论分组
+func (ac *ArticleController) HandleComments(c *gin.Context) {
+	id, ok := articleID(c)
+	if !ok {
+		return
+	}
+
+	comments, err := ac.articleService.ListComments(c.Request.Context(), id)
+	if err != nil {
+		handleArticleError(c, "查询评论失败", err)
+		return
+	}
+	response.Success(c, "查询成功", commentThreads(comments))
+}
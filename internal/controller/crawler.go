@@ -1,18 +1,32 @@
 package controller
 
 import (
+	"bufio"
+	"crawler/internal/middleware"
+	"crawler/internal/progress"
+	"crawler/internal/repository"
 	"crawler/internal/service"
+	"crawler/pkg/errcode"
 	"crawler/pkg/logger"
 	"crawler/pkg/response"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/gin-contrib/sse"
 	"github.com/gin-gonic/gin"
 )
 
 // ICrawlerController 爬虫控制器接口
 type ICrawlerController interface {
 	HandleCrawl(c *gin.Context)
+	HandleGetJob(c *gin.Context)
+	HandleJobLogs(c *gin.Context)
+	HandleJobEvents(c *gin.Context)
+	HandleJobArtifacts(c *gin.Context)
+	HandleJobArtifact(c *gin.Context)
 }
 
 type CrawlerController struct {
@@ -25,36 +39,303 @@ func NewCrawlerController(service service.ICrawlerService) ICrawlerController {
 	}
 }
 
+type crawlRequest struct {
+	Debug bool   `json:"debug"` // 为本次任务临时开启调试日志
+	Async bool   `json:"async"` // 后台执行，立即返回任务ID
+	Mode  string `json:"mode"`  // 爬取模式: auto/full/incremental，为空时使用配置
+}
+
+// crawlRunView 对外展示的任务信息，不包含日志
+type crawlRunView struct {
+	ID           string     `json:"id"`
+	Account      string     `json:"account"`
+	Source       string     `json:"source"`
+	Mode         string     `json:"mode"`
+	Status       string     `json:"status"`
+	Error        string     `json:"error,omitempty"`
+	FailReason   string     `json:"fail_reason,omitempty"`
+	Proxy        string     `json:"proxy,omitempty"`
+	ArticleCount int        `json:"article_count"`
+	Retries      int        `json:"retries"`
+	Deleted      int        `json:"deleted"`
+	Restored     int        `json:"restored"`
+	Revisions    int        `json:"revisions"`
+	Comments     int        `json:"comments"`
+	StartedAt    time.Time  `json:"started_at"`
+	FinishedAt   *time.Time `json:"finished_at"`
+}
+
+func newCrawlRunView(run *repository.CrawlRun) crawlRunView {
+	return crawlRunView{
+		ID:           run.ID,
+		Account:      run.Account,
+		Source:       run.Source,
+		Mode:         run.Mode,
+		Status:       run.Status,
+		Error:        run.Error,
+		FailReason:   run.FailReason,
+		Proxy:        run.Proxy,
+		ArticleCount: run.ArticleCount,
+		Retries:      run.Retries,
+		Deleted:      run.DeletedCount,
+		Restored:     run.RestoredCount,
+		Revisions:    run.RevisionCount,
+		Comments:     run.CommentCount,
+		StartedAt:    run.StartedAt,
+		FinishedAt:   run.FinishedAt,
+	}
+}
+
 func (cc *CrawlerController) HandleCrawl(c *gin.Context) {
 	start := time.Now()
-	logger.Info("收到爬取请求",
-		"trace_id", c.GetString("trace_id"),
-	)
+	log := logger.FromContext(c.Request.Context())
+
+	// 请求体可选，未传时使用默认参数
+	var req crawlRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			response.FromError(c, errcode.Wrap(errcode.InvalidRequest, "请求参数错误", err))
+			return
+		}
+	}
+	log.Info("收到爬取请求", "debug", req.Debug, "async", req.Async, "mode", req.Mode)
 
 	if err := cc.crawlerService.CheckPrerequisites(); err != nil {
-		logger.Error("前置条件检查失败",
+		log.Error("前置条件检查失败",
 			"error", err,
 			"duration", time.Since(start).String(),
-			"trace_id", c.GetString("trace_id"),
 		)
-		response.Error(c, http.StatusBadRequest, "爬取前置条件不满足: "+err.Error())
+		response.FromError(c, err)
+		return
+	}
+
+	opts := service.CrawlOptions{
+		Source: service.SourceAPI,
+		Debug:  req.Debug,
+		Mode:   req.Mode,
+	}
+
+	if req.Async {
+		run, err := cc.crawlerService.StartCrawl(c.Request.Context(), opts)
+		if err != nil {
+			cc.handleCrawlError(c, err, start)
+			return
+		}
+		log.Info("爬取任务已提交", "job_id", run.ID)
+		c.JSON(http.StatusAccepted, response.Response{
+			Code:    0,
+			Message: "爬取任务已提交",
+			Data:    newCrawlRunView(run),
+			TraceID: c.GetString(middleware.TraceIDKey),
+		})
 		return
 	}
 
-	if err := cc.crawlerService.ExecuteCrawl(); err != nil {
-		logger.Error("爬取失败",
+	run, err := cc.crawlerService.ExecuteCrawl(c.Request.Context(), opts)
+	if err != nil {
+		cc.handleCrawlError(c, err, start)
+		return
+	}
+
+	log.Info("爬取完成",
+		"job_id", run.ID,
+		"duration", time.Since(start).String(),
+	)
+
+	response.Success(c, "爬取成功", newCrawlRunView(run))
+}
+
+func (cc *CrawlerController) handleCrawlError(c *gin.Context, err error, start time.Time) {
+	log := logger.FromContext(c.Request.Context())
+
+	switch code := errcode.Of(err); code {
+	case errcode.Throttled, errcode.Blocked, errcode.NotLoggedIn:
+		log.Warn("爬取请求未执行",
+			"reason", code.String(),
 			"error", err,
 			"duration", time.Since(start).String(),
-			"trace_id", c.GetString("trace_id"),
 		)
-		response.Error(c, http.StatusInternalServerError, "爬取失败: "+err.Error())
+	default:
+		log.Error("爬取失败",
+			"reason", code.String(),
+			"error", err,
+			"duration", time.Since(start).String(),
+		)
+	}
+	response.FromError(c, err)
+}
+
+func (cc *CrawlerController) HandleGetJob(c *gin.Context) {
+	run, ok := cc.findRun(c)
+	if !ok {
 		return
 	}
+	response.Success(c, "查询成功", newCrawlRunView(run))
+}
 
-	logger.Info("爬取完成",
-		"duration", time.Since(start).String(),
-		"trace_id", c.GetString("trace_id"),
-	)
+// HandleJobLogs 以 JSON Lines 返回任务日志，任务进行中时持续推送直到任务结束。
+// 先检查进行中的日志再查询任务记录：任务结束时先保存记录再移除进行中的日志，这样读到的记录总是包含完整日志
+func (cc *CrawlerController) HandleJobLogs(c *gin.Context) {
+	capture, active := cc.crawlerService.RunLogs(c.Param("id"))
+	if !active {
+		run, ok := cc.findRun(c)
+		if !ok {
+			return
+		}
+		c.Header("Content-Type", "application/x-ndjson; charset=utf-8")
+		c.Status(http.StatusOK)
+		writeLogLines(c.Writer, run.Logs)
+		return
+	}
+
+	c.Header("Content-Type", "application/x-ndjson; charset=utf-8")
+
+	// 流式输出不受服务端写超时限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	offset := 0
+	c.Stream(func(w io.Writer) bool {
+		lines, updated, closed := capture.Since(offset)
+		for _, line := range lines {
+			io.WriteString(w, line+"\n")
+		}
+		offset += len(lines)
+		if closed {
+			return false
+		}
+		if len(lines) > 0 {
+			return true
+		}
+
+		select {
+		case <-updated:
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// HandleJobEvents 以 Server-Sent Events 推送任务进度，支持 Last-Event-ID 断线续传。
+// 任务已结束时只推送一条结束事件，结束事件由进行中的事件流不存在之后读到的任务记录得出，不会读到结束前的状态。
+func (cc *CrawlerController) HandleJobEvents(c *gin.Context) {
+	stream, active := cc.crawlerService.RunEvents(c.Param("id"))
+	if !active {
+		run, ok := cc.findRun(c)
+		if !ok {
+			return
+		}
+		setEventStreamHeaders(c)
+		finishedAt := run.UpdatedAt
+		if run.FinishedAt != nil {
+			finishedAt = *run.FinishedAt
+		}
+		c.Render(-1, sse.Event{
+			Event: service.RunEventType(run),
+			Data: progress.Event{
+				Type:  service.RunEventType(run),
+				JobID: run.ID,
+				Time:  finishedAt,
+				Data:  service.RunEventData(run),
+			},
+		})
+		return
+	}
+
+	setEventStreamHeaders(c)
+	// 流式输出不受服务端写超时限制
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	seq, _ := strconv.Atoi(c.GetHeader("Last-Event-ID"))
+	c.Stream(func(w io.Writer) bool {
+		events, updated, closed := stream.Since(seq)
+		for _, event := range events {
+			c.Render(-1, sse.Event{
+				Id:    strconv.Itoa(event.Seq),
+				Event: event.Type,
+				Data:  event,
+			})
+			seq = event.Seq
+		}
+		if closed {
+			return false
+		}
+		if len(events) > 0 {
+			return true
+		}
+
+		select {
+		case <-updated:
+			return true
+		case <-time.After(15 * time.Second):
+			// 保持连接，避免被代理断开
+			io.WriteString(w, ": keep-alive\n\n")
+			return true
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
+
+// HandleJobArtifacts 列出任务失败时保存的截图、HTML 和 trace 文件
+func (cc *CrawlerController) HandleJobArtifacts(c *gin.Context) {
+	artifacts, err := cc.crawlerService.ListArtifacts(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errcode.Of(err) != errcode.NotFound {
+			logger.FromContext(c.Request.Context()).Error("查询任务现场文件失败",
+				"job_id", c.Param("id"),
+				"error", err,
+			)
+		}
+		response.FromError(c, err)
+		return
+	}
+	response.Success(c, "查询成功", artifacts)
+}
 
-	response.Success(c, "爬取成功", nil)
+// HandleJobArtifact 下载任务现场文件
+func (cc *CrawlerController) HandleJobArtifact(c *gin.Context) {
+	path, err := cc.crawlerService.ArtifactPath(c.Request.Context(), c.Param("id"), c.Param("name"))
+	if err != nil {
+		if errcode.Of(err) != errcode.NotFound {
+			logger.FromContext(c.Request.Context()).Error("查询任务现场文件失败",
+				"job_id", c.Param("id"),
+				"error", err,
+			)
+		}
+		response.FromError(c, err)
+		return
+	}
+	c.FileAttachment(path, c.Param("name"))
+}
+
+func (cc *CrawlerController) findRun(c *gin.Context) (*repository.CrawlRun, bool) {
+	run, err := cc.crawlerService.GetRun(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errcode.Of(err) != errcode.NotFound {
+			logger.FromContext(c.Request.Context()).Error("查询爬取任务失败",
+				"job_id", c.Param("id"),
+				"error", err,
+			)
+		}
+		response.FromError(c, err)
+		return nil, false
+	}
+	return run, true
+}
+
+func setEventStreamHeaders(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+}
+
+func writeLogLines(w io.Writer, logs string) {
+	scanner := bufio.NewScanner(strings.NewReader(logs))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			io.WriteString(w, line+"\n")
+		}
+	}
 }
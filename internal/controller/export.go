@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"context"
+	"crawler/internal/service"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/response"
+	"errors"
+	"io"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IExportController 文章导出控制器接口
+type IExportController interface {
+	HandleMarkdown(c *gin.Context)
+}
+
+type ExportController struct {
+	exportService service.IExportService
+	timeout       time.Duration // 导出的最长时间，小于服务的写超时
+}
+
+func NewExportController(service service.IExportService, timeout time.Duration) IExportController {
+	return &ExportController{
+		exportService: service,
+		timeout:       timeout,
+	}
+}
+
+type exportRequest struct {
+	IDs            []int64  `json:"ids"`             // 要导出的文章ID，为空时导出全部
+	IncludeDeleted bool     `json:"include_deleted"` // 是否包含已删除的文章
+	Tags           []string `json:"tags"`            // 追加的标签
+	SkipImages     bool     `json:"skip_images"`     // 不下载图片
+}
+
+// HandleMarkdown 导出 Markdown 并返回 zip 文件
+func (ec *ExportController) HandleMarkdown(c *gin.Context) {
+	var req exportRequest
+	// 请求体可以为空，此时导出全部文章
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		response.FromError(c, errcode.Wrap(errcode.InvalidRequest, "请求参数错误", err))
+		return
+	}
+
+	// 超过写超时后连接会被直接断开，提前结束导出以便返回错误
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ec.timeout)
+	defer cancel()
+	result, err := ec.exportService.ExportMarkdown(ctx, service.ExportOptions{
+		IDs:            req.IDs,
+		IncludeDeleted: req.IncludeDeleted,
+		Tags:           req.Tags,
+		SkipImages:     req.SkipImages,
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errcode.Wrap(errcode.Timeout, "导出超时", err)
+	}
+	if err != nil {
+		if errcode.Of(err) != errcode.NotFound {
+			logger.FromContext(c.Request.Context()).Error("导出文章失败",
+				"error", err,
+			)
+		}
+		response.FromError(c, err)
+		return
+	}
+
+	// 接口导出在临时目录中生成，发送后删除
+	defer func() {
+		if err := result.Cleanup(); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("删除临时导出文件失败", "error", err)
+		}
+	}()
+
+	c.Header("X-Export-Articles", strconv.Itoa(result.Articles))
+	c.Header("X-Export-Failed-Images", strconv.Itoa(result.FailedImages))
+	c.FileAttachment(result.ZipPath, filepath.Base(result.ZipPath))
+}
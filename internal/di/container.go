@@ -1,7 +1,10 @@
 package di
 
 import (
+	"crawler/internal/browser"
 	"crawler/internal/controller"
+	"crawler/internal/notify"
+	"crawler/internal/proxy"
 	"crawler/internal/repository"
 	"crawler/internal/router"
 	"crawler/internal/service"
@@ -12,45 +15,114 @@ import (
 )
 
 type Container struct {
-	Config         *config.Config
-	DB             *gorm.DB
-	ArticleRepo    repository.ArticleRepository
-	CrawlerService service.ICrawlerService
-	CrawlerHandler controller.ICrawlerController
-	Router         *router.Router
+	Config           *config.Config
+	DB               *gorm.DB
+	Browsers         browser.Manager
+	Proxies          proxy.Pool
+	ArticleRepo      repository.ArticleRepository
+	CrawlRunRepo     repository.CrawlRunRepository
+	APIKeyRepo       repository.APIKeyRepository
+	RevisionRepo     repository.ArticleRevisionRepository
+	CommentRepo      repository.CommentRepository
+	AccountRepo      repository.AccountMetricsRepository
+	AnalyticsRepo    repository.AnalyticsRepository
+	CrawlerService   service.ICrawlerService
+	APIKeyService    service.IAPIKeyService
+	ArticleService   service.IArticleService
+	AccountService   service.IAccountService
+	AnalyticsService service.IAnalyticsService
+	ExportService    service.IExportService
+	CrawlerHandler   controller.ICrawlerController
+	APIKeyHandler    controller.IAPIKeyController
+	AdminHandler     controller.IAdminController
+	ArticleHandler   controller.IArticleController
+	AccountHandler   controller.IAccountController
+	AnalyticsHandler controller.IAnalyticsController
+	ExportHandler    controller.IExportController
+	Router           *router.Router
 }
 
 func NewContainer(cfg *config.Config, db *gorm.DB) (*Container, error) {
 	// 1. Repository
 	articleRepo := repository.NewGormArticleRepository(db)
+	apiKeyRepo := repository.NewGormAPIKeyRepository(db)
+	crawlRunRepo := repository.NewGormCrawlRunRepository(db)
+	revisionRepo := repository.NewGormArticleRevisionRepository(db)
+	commentRepo := repository.NewGormCommentRepository(db)
+	accountRepo := repository.NewGormAccountMetricsRepository(db)
+	analyticsRepo := repository.NewGormAnalyticsRepository(db)
 
 	// 2. Service
-	crawlerService := service.NewCrawlerService(cfg, articleRepo)
+	browsers := browser.NewManager(cfg.Browser)
+	proxies := proxy.NewPool(cfg.Proxy)
+	notifier := notify.NewNotifier(cfg.Notify)
+	crawlerService := service.NewCrawlerService(cfg, browsers, proxies, notifier, articleRepo, crawlRunRepo, revisionRepo, commentRepo, accountRepo, analyticsRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo)
+	articleService := service.NewArticleService(articleRepo, revisionRepo, commentRepo)
+	accountService := service.NewAccountService(accountRepo)
+	analyticsService := service.NewAnalyticsService(analyticsRepo)
+	exportService := service.NewExportService(cfg, proxies, articleRepo, revisionRepo)
 
 	// 3. Controller
 	crawlerController := controller.NewCrawlerController(crawlerService)
+	apiKeyController := controller.NewAPIKeyController(apiKeyService)
+	adminController := controller.NewAdminController()
+	articleController := controller.NewArticleController(articleService)
+	accountController := controller.NewAccountController(accountService)
+	analyticsController := controller.NewAnalyticsController(analyticsService)
+	exportController := controller.NewExportController(exportService, cfg.Export.TimeoutOrDefault(cfg.Server.WriteTimeout))
 
 	// 4. Router
-	r, err := router.NewRouter(cfg, crawlerController)
+	r, err := router.NewRouter(cfg, router.Controllers{
+		Crawler:   crawlerController,
+		APIKey:    apiKeyController,
+		Admin:     adminController,
+		Article:   articleController,
+		Account:   accountController,
+		Analytics: analyticsController,
+		Export:    exportController,
+	}, apiKeyService)
 	if err != nil {
 		return nil, fmt.Errorf("初始化路由失败: %w", err)
 	}
 
 	return &Container{
-		Config:         cfg,
-		DB:             db,
-		ArticleRepo:    articleRepo,
-		CrawlerService: crawlerService,
-		CrawlerHandler: crawlerController,
-		Router:         r,
+		Config:           cfg,
+		DB:               db,
+		Browsers:         browsers,
+		Proxies:          proxies,
+		ArticleRepo:      articleRepo,
+		CrawlRunRepo:     crawlRunRepo,
+		APIKeyRepo:       apiKeyRepo,
+		RevisionRepo:     revisionRepo,
+		CommentRepo:      commentRepo,
+		AccountRepo:      accountRepo,
+		AnalyticsRepo:    analyticsRepo,
+		CrawlerService:   crawlerService,
+		APIKeyService:    apiKeyService,
+		ArticleService:   articleService,
+		AccountService:   accountService,
+		AnalyticsService: analyticsService,
+		ExportService:    exportService,
+		CrawlerHandler:   crawlerController,
+		APIKeyHandler:    apiKeyController,
+		AdminHandler:     adminController,
+		ArticleHandler:   articleController,
+		AccountHandler:   accountController,
+		AnalyticsHandler: analyticsController,
+		ExportHandler:    exportController,
+		Router:           r,
 	}, nil
 }
 
 // 添加清理方法
 func (c *Container) ReleaseResources() {
 	// 按依赖关系的反向顺序清理资源
-	if c.CrawlerService != nil {
-		c.CrawlerService.Cleanup()
+	if c.Browsers != nil {
+		c.Browsers.Close()
+	}
+	if c.Proxies != nil {
+		c.Proxies.Close()
 	}
 
 	if c.DB != nil {
@@ -0,0 +1,32 @@
+package export
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ValidFilename 将字符串转换为有效的文件名，与 zhihu-download 的 get_valid_filename 一致：
+// 去掉开头的非字母字符（包括数字），去掉首尾空白，空格替换为下划线，只保留字母、数字、下划线和连字符
+func ValidFilename(s string) string {
+	s = strings.TrimLeftFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "_")
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_' || r == '-' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// ArticleFilename 文章的 Markdown 文件名（不含扩展名），有发布时间时为 "{日期}.{标题}"
+func ArticleFilename(title string, publishedAt *time.Time) string {
+	name := ValidFilename(title)
+	if name == "" {
+		name = "Untitled"
+	}
+	if publishedAt != nil {
+		name = publishedAt.Format(time.DateOnly) + "." + name
+	}
+	return name
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidFilename(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Go 并发 入门", "Go_并发_入门"},
+		{"2024年终总结", "年终总结"},
+		{"  123 - 《标题》：副标题?", "标题副标题"},
+		{"a/b\\c:d*e", "abcde"},
+		{"hello-world_v2", "hello-world_v2"},
+		{"!!!", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ValidFilename(tt.in); got != tt.want {
+			t.Errorf("ValidFilename(%q) = %q，期望 %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestArticleFilename(t *testing.T) {
+	published := time.Date(2024, 12, 1, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		title       string
+		publishedAt *time.Time
+		want        string
+	}{
+		{"Go 并发", &published, "2024-12-01.Go_并发"},
+		{"Go 并发", nil, "Go_并发"},
+		{"2024", &published, "2024-12-01.Untitled"},
+		{"？？", nil, "Untitled"},
+	}
+	for _, tt := range tests {
+		if got := ArticleFilename(tt.title, tt.publishedAt); got != tt.want {
+			t.Errorf("ArticleFilename(%q) = %q，期望 %q", tt.title, got, tt.want)
+		}
+	}
+}
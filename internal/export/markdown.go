@@ -0,0 +1,313 @@
+package export
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	blankLines = regexp.MustCompile(`\n{3,}`)
+	spaces     = regexp.MustCompile(`[ \t\r\n]+`)
+)
+
+// ImageFunc 返回图片在 Markdown 中引用的地址，用于把图片替换为本地文件
+type ImageFunc func(src string) string
+
+// ToMarkdown 将知乎文章正文 HTML 转换为 Markdown，image 为空时保留图片原地址
+func ToMarkdown(fragment string, image ImageFunc) (string, error) {
+	parent := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	nodes, err := html.ParseFragment(strings.NewReader(fragment), parent)
+	if err != nil {
+		return "", fmt.Errorf("解析正文 HTML 失败: %w", err)
+	}
+	if image == nil {
+		image = func(src string) string { return src }
+	}
+
+	c := &converter{image: image}
+	var b strings.Builder
+	for _, n := range nodes {
+		c.node(&b, n)
+	}
+	return tidy(b.String()), nil
+}
+
+type converter struct {
+	image ImageFunc
+}
+
+// node 按元素类型输出 Markdown，块级元素前后用空行分隔，最后统一整理空行
+func (c *converter) node(b *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(spaces.ReplaceAllString(n.Data, " "))
+		return
+	case html.ElementNode:
+	default:
+		c.children(b, n)
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript:
+	case atom.P, atom.Div, atom.Section, atom.Figure, atom.Figcaption:
+		b.WriteString("\n\n")
+		c.children(b, n)
+		b.WriteString("\n\n")
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		level := int(n.Data[1] - '0')
+		fmt.Fprintf(b, "\n\n%s %s\n\n", strings.Repeat("#", level), c.inline(n))
+	case atom.Br:
+		b.WriteString("\n")
+	case atom.Hr:
+		b.WriteString("\n\n---\n\n")
+	case atom.Strong, atom.B:
+		wrap(b, "**", c.inline(n))
+	case atom.Em, atom.I:
+		wrap(b, "*", c.inline(n))
+	case atom.Code:
+		wrap(b, "`", textContent(n))
+	case atom.Pre:
+		fmt.Fprintf(b, "\n\n```%s\n%s\n```\n\n", codeLanguage(n), strings.TrimRight(textContent(n), "\n"))
+	case atom.A:
+		c.link(b, n)
+	case atom.Img:
+		c.img(b, n)
+	case atom.Blockquote:
+		b.WriteString("\n\n" + prefixLines(c.block(n), "> ", "> ") + "\n\n")
+	case atom.Ul, atom.Ol:
+		c.list(b, n)
+	case atom.Table:
+		c.table(b, n)
+	case atom.Span:
+		if hasClass(n, "ztext-math") {
+			c.math(b, n)
+			return
+		}
+		c.children(b, n)
+	default:
+		c.children(b, n)
+	}
+}
+
+func (c *converter) children(b *strings.Builder, n *html.Node) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		c.node(b, child)
+	}
+}
+
+// inline 返回元素内容转换后的单行文本
+func (c *converter) inline(n *html.Node) string {
+	var b strings.Builder
+	c.children(&b, n)
+	return strings.TrimSpace(spaces.ReplaceAllString(b.String(), " "))
+}
+
+// block 返回元素内容转换后的多行文本
+func (c *converter) block(n *html.Node) string {
+	var b strings.Builder
+	c.children(&b, n)
+	return tidy(b.String())
+}
+
+// link 输出链接，知乎站外跳转链接还原为目标地址，链接卡片使用 data-text 作为标题
+func (c *converter) link(b *strings.Builder, n *html.Node) {
+	href := targetURL(attr(n, "href"))
+	text := c.inline(n)
+	if title := attr(n, "data-text"); title != "" && (text == "" || hasClass(n, "LinkCard")) {
+		text = title
+	}
+	if href == "" {
+		b.WriteString(text)
+		return
+	}
+	if text == "" {
+		text = href
+	}
+	fmt.Fprintf(b, "[%s](%s)", text, href)
+}
+
+// img 输出图片，优先使用原图地址，跳过懒加载占位图
+func (c *converter) img(b *strings.Builder, n *html.Node) {
+	src := ""
+	for _, key := range []string{"data-original", "data-actualsrc", "src"} {
+		if v := attr(n, key); v != "" && !strings.HasPrefix(v, "data:") {
+			src = v
+			break
+		}
+	}
+	if src == "" {
+		return
+	}
+	fmt.Fprintf(b, "\n\n![%s](%s)\n\n", attr(n, "alt"), c.image(src))
+}
+
+// math 输出公式，带 \tag 的公式作为独立的公式块
+func (c *converter) math(b *strings.Builder, n *html.Node) {
+	tex := attr(n, "data-tex")
+	if tex == "" {
+		tex = textContent(n)
+	}
+	switch {
+	case strings.Contains(tex, "$"):
+		b.WriteString(tex)
+	case strings.Contains(tex, `\tag`):
+		fmt.Fprintf(b, "\n\n$$%s$$\n\n", tex)
+	default:
+		fmt.Fprintf(b, "$%s$", tex)
+	}
+}
+
+// list 输出有序或无序列表，列表项的后续行缩进到内容对齐
+func (c *converter) list(b *strings.Builder, n *html.Node) {
+	b.WriteString("\n\n")
+	index := 0
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.DataAtom != atom.Li {
+			continue
+		}
+		index++
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = fmt.Sprintf("%d. ", index)
+		}
+		b.WriteString(prefixLines(c.block(li), marker, strings.Repeat(" ", len(marker))) + "\n")
+	}
+	b.WriteString("\n")
+}
+
+// table 输出表格，第一行作为表头
+func (c *converter) table(b *strings.Builder, n *html.Node) {
+	var rows [][]string
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.DataAtom != atom.Tr {
+				walk(child)
+				continue
+			}
+			var cells []string
+			for cell := child.FirstChild; cell != nil; cell = cell.NextSibling {
+				if cell.DataAtom == atom.Td || cell.DataAtom == atom.Th {
+					cells = append(cells, strings.ReplaceAll(c.inline(cell), "|", `\|`))
+				}
+			}
+			rows = append(rows, cells)
+		}
+	}
+	walk(n)
+	if len(rows) == 0 {
+		return
+	}
+
+	b.WriteString("\n\n")
+	for i, row := range rows {
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString(strings.Repeat("| --- ", len(row)) + "|\n")
+		}
+	}
+	b.WriteString("\n")
+}
+
+// targetURL 还原知乎站外链接跳转（link.zhihu.com/?target=...）的目标地址
+func targetURL(href string) string {
+	u, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	if target := u.Query().Get("target"); target != "" && strings.HasSuffix(u.Host, "link.zhihu.com") {
+		return target
+	}
+	if strings.HasPrefix(href, "//") {
+		return "https:" + href
+	}
+	return href
+}
+
+func codeLanguage(pre *html.Node) string {
+	if lang := attr(pre, "lang"); lang != "" {
+		return lang
+	}
+	for n := pre.FirstChild; n != nil; n = n.NextSibling {
+		for _, class := range strings.Fields(attr(n, "class")) {
+			if lang, ok := strings.CutPrefix(class, "language-"); ok {
+				return lang
+			}
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	var b strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.DataAtom == atom.Br {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(textContent(child))
+	}
+	return b.String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, c := range strings.Fields(attr(n, "class")) {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// wrap 用标记包裹文本，只有空白时不加标记
+func wrap(b *strings.Builder, mark, text string) {
+	if strings.TrimSpace(text) == "" {
+		b.WriteString(text)
+		return
+	}
+	b.WriteString(mark + text + mark)
+}
+
+// prefixLines 第一行加 first 前缀，其余非空行加 rest 前缀
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + line
+		case line != "":
+			lines[i] = rest + line
+		case strings.TrimSpace(rest) != "":
+			lines[i] = strings.TrimRight(rest, " ")
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tidy 去掉行尾空白，合并多余空行
+func tidy(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	s = strings.Join(lines, "\n")
+	return strings.TrimSpace(blankLines.ReplaceAllString(s, "\n\n"))
+}
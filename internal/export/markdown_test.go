@@ -0,0 +1,97 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			"标题和段落",
+			`<h2>小节</h2><p>第一段 <b>加粗</b> 和 <em>斜体</em></p><p>第二段<br>换行</p>`,
+			"## 小节\n\n第一段 **加粗** 和 *斜体*\n\n第二段\n换行",
+		},
+		{
+			"无序和有序列表",
+			`<ul><li>苹果</li><li>香蕉</li></ul><ol><li>第一步</li><li><p>第二步</p><p>补充说明</p></li></ol>`,
+			"- 苹果\n- 香蕉\n\n1. 第一步\n2. 第二步\n\n   补充说明",
+		},
+		{
+			"表格第一行作为表头",
+			`<table><tbody><tr><th>名称</th><th>说明</th></tr><tr><td>a|b</td><td><b>粗</b></td></tr></tbody></table>`,
+			"| 名称 | 说明 |\n| --- | --- |\n| a\\|b | **粗** |",
+		},
+		{
+			"行内代码和代码块",
+			`<p>调用 <code>fmt.Println</code> 输出</p><div class="highlight"><pre><code class="language-go">func main() {
+	fmt.Println("hi")
+}
+</code></pre></div>`,
+			"调用 `fmt.Println` 输出\n\n```go\nfunc main() {\n\tfmt.Println(\"hi\")\n}\n```",
+		},
+		{
+			"公式",
+			`<p>质能方程 <span class="ztext-math" data-tex="E=mc^2">E=mc^2</span></p>` +
+				`<p><span class="ztext-math" data-tex="a^2+b^2=c^2 \tag{1}">a^2+b^2=c^2</span></p>`,
+			"质能方程 $E=mc^2$\n\n$$a^2+b^2=c^2 \\tag{1}$$",
+		},
+		{
+			"站外链接还原目标地址",
+			`<p><a href="https://link.zhihu.com/?target=https%3A//go.dev/doc" class="external">Go 文档</a>` +
+				` <a href="//www.zhihu.com/people/x">作者</a></p>`,
+			"[Go 文档](https://go.dev/doc) [作者](https://www.zhihu.com/people/x)",
+		},
+		{
+			"链接卡片使用 data-text 作为标题",
+			`<a href="https://zhuanlan.zhihu.com/p/1" data-text="另一篇文章" class="LinkCard">https://zhuanlan.zhihu.com/p/1</a>`,
+			"[另一篇文章](https://zhuanlan.zhihu.com/p/1)",
+		},
+		{
+			"懒加载图片使用原图地址",
+			`<figure><img src="data:image/svg+xml;utf8,&lt;svg&gt;&lt;/svg&gt;" data-actualsrc="https://pic1.zhimg.com/v2-a_b.jpg" data-original="https://pic1.zhimg.com/v2-a_r.jpg" alt="示意图"><figcaption>图 1</figcaption></figure>`,
+			"![示意图](local:https://pic1.zhimg.com/v2-a_r.jpg)\n\n图 1",
+		},
+		{
+			"只有占位图时跳过图片",
+			`<p>前</p><img src="data:image/gif;base64,R0lGOD"><p>后</p>`,
+			"前\n\n后",
+		},
+		{
+			"引用",
+			`<blockquote>第一行<br>第二行</blockquote>`,
+			"> 第一行\n> 第二行",
+		},
+		{
+			"忽略脚本和样式",
+			`<p>正文</p><script>alert(1)</script><style>p{}</style>`,
+			"正文",
+		},
+	}
+	image := func(src string) string { return "local:" + src }
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ToMarkdown(tt.html, image)
+			if err != nil {
+				t.Fatalf("ToMarkdown 返回错误: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ToMarkdown =\n%s\n期望\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToMarkdownWithoutImageFunc(t *testing.T) {
+	got, err := ToMarkdown(`<img src="https://pic1.zhimg.com/v2-a.jpg">`, nil)
+	if err != nil {
+		t.Fatalf("ToMarkdown 返回错误: %v", err)
+	}
+	if !strings.Contains(got, "(https://pic1.zhimg.com/v2-a.jpg)") {
+		t.Errorf("没有 image 时应保留原地址，得到 %q", got)
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"crawler/internal/service"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/response"
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+	PrincipalKey = "principal"
+)
+
+// Auth 认证中间件，支持应用账号的 Basic 认证（拥有全部权限）和 API 密钥
+func Auth(cfg *config.Config, keyService service.IAPIKeyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if username, password, ok := c.Request.BasicAuth(); ok {
+			if !matchAppCredentials(cfg, username, password) {
+				response.FromError(c, errcode.New(errcode.Unauthorized, "用户名或密码错误"))
+				c.Abort()
+				return
+			}
+			c.Set(PrincipalKey, &service.Principal{
+				Name:   username,
+				Scopes: []string{service.ScopeAdmin},
+			})
+			c.Next()
+			return
+		}
+
+		rawKey := extractAPIKey(c)
+		if rawKey == "" {
+			c.Header("WWW-Authenticate", `Basic realm="crawler"`)
+			response.FromError(c, errcode.New(errcode.Unauthorized, "缺少认证信息"))
+			c.Abort()
+			return
+		}
+
+		principal, err := keyService.Authenticate(c.Request.Context(), rawKey)
+		if err != nil {
+			if errcode.Of(err) != errcode.Unauthorized {
+				logger.FromContext(c.Request.Context()).Error("API密钥认证失败",
+					"error", err,
+				)
+			}
+			response.FromError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set(PrincipalKey, principal)
+		c.Next()
+	}
+}
+
+// RequireScope 要求调用方拥有指定权限，需在 Auth 之后使用
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := CurrentPrincipal(c)
+		if principal == nil {
+			response.FromError(c, errcode.New(errcode.Unauthorized, "缺少认证信息"))
+			c.Abort()
+			return
+		}
+		if !principal.HasScope(scope) {
+			response.FromError(c, errcode.New(errcode.Forbidden, "权限不足，需要: "+scope))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// CurrentPrincipal 获取当前请求的调用方，未认证时返回 nil
+func CurrentPrincipal(c *gin.Context) *service.Principal {
+	value, ok := c.Get(PrincipalKey)
+	if !ok {
+		return nil
+	}
+	principal, _ := value.(*service.Principal)
+	return principal
+}
+
+func extractAPIKey(c *gin.Context) string {
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return strings.TrimSpace(key)
+	}
+	auth := c.GetHeader("Authorization")
+	if len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return ""
+}
+
+func matchAppCredentials(cfg *config.Config, username, password string) bool {
+	// 未配置密码时禁用 Basic 认证
+	if cfg.App.Password == "" {
+		return false
+	}
+	userOK := subtle.ConstantTimeCompare([]byte(username), []byte(cfg.App.Username)) == 1
+	passOK := subtle.ConstantTimeCompare([]byte(password), []byte(cfg.App.Password)) == 1
+	return userOK && passOK
+}
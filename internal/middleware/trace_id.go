@@ -1,24 +1,51 @@
 package middleware
 
 import (
+	"crawler/pkg/logger"
+
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
 	TraceIDHeader = "X-Request-ID"
 	TraceIDKey    = "trace_id"
+	RequestIDKey  = "request_id"
+	SpanIDKey     = "span_id"
 )
 
+// TraceID 使用 OpenTelemetry 的 TraceID 作为 trace_id，使日志与链路追踪可以用同一个ID关联。
+// 客户端传入的 X-Request-ID 作为单独的 request_id 记录，未传入时与 trace_id 相同。
+// 同时在请求上下文中安装带 trace_id、span_id 和 request_id 的日志实例。需在 Tracing 之后使用。
 func TraceID() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		traceID := c.GetHeader(TraceIDHeader)
-		if traceID == "" {
-			traceID = uuid.New().String()
+		span := trace.SpanFromContext(c.Request.Context())
+
+		sc := span.SpanContext()
+		traceID := uuid.New().String()
+		if sc.HasTraceID() {
+			traceID = sc.TraceID().String()
+		}
+		requestID := c.GetHeader(TraceIDHeader)
+		if requestID == "" {
+			requestID = traceID
 		}
+		span.SetAttributes(attribute.String("http.request_id", requestID))
 
 		c.Set(TraceIDKey, traceID)
-		c.Header(TraceIDHeader, traceID)
+		c.Set(RequestIDKey, requestID)
+		c.Header(TraceIDHeader, requestID)
+		fields := map[string]interface{}{
+			TraceIDKey:   traceID,
+			RequestIDKey: requestID,
+		}
+		if sc.HasSpanID() {
+			fields[SpanIDKey] = sc.SpanID().String()
+		}
+		requestLogger := logger.WithFields(fields).WithModule(logger.ModuleHTTP)
+		c.Request = c.Request.WithContext(logger.WithContext(c.Request.Context(), requestLogger))
 
 		c.Next()
 	}
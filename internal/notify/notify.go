@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crawler/pkg/config"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// 通知事件类型
+const (
+	EventCrawlFailed = "crawl_failed"
+)
+
+// Notification 发送到 webhook 的通知内容
+type Notification struct {
+	Event      string    `json:"event"`
+	JobID      string    `json:"job_id"`
+	Account    string    `json:"account"`
+	Source     string    `json:"source"`
+	Mode       string    `json:"mode"`
+	Reason     string    `json:"reason"`
+	Error      string    `json:"error,omitempty"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// Notifier 发送任务通知
+type Notifier interface {
+	// ShouldNotify 判断失败原因是否需要通知
+	ShouldNotify(reason string) bool
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NewNotifier 根据配置创建通知器，未配置 webhook 时返回不发送任何通知的实现
+func NewNotifier(cfg config.NotifyConfig) Notifier {
+	if cfg.WebhookURL == "" {
+		return nopNotifier{}
+	}
+	reasons := make(map[string]bool)
+	for _, reason := range cfg.ReasonsOrDefault() {
+		reasons[reason] = true
+	}
+	return &webhookNotifier{
+		url:     cfg.WebhookURL,
+		reasons: reasons,
+		client:  &http.Client{Timeout: cfg.TimeoutOrDefault()},
+	}
+}
+
+type nopNotifier struct{}
+
+func (nopNotifier) ShouldNotify(string) bool                   { return false }
+func (nopNotifier) Notify(context.Context, Notification) error { return nil }
+
+// webhookNotifier 以 JSON POST 到配置的地址，非 2xx 响应视为失败
+type webhookNotifier struct {
+	url     string
+	reasons map[string]bool
+	client  *http.Client
+}
+
+func (w *webhookNotifier) ShouldNotify(reason string) bool {
+	return w.reasons[reason]
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, n Notification) error {
+	body, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("发送通知失败: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("通知地址返回 %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package progress
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 事件类型
+const (
+	EventStarted          = "started"
+	EventBrowserLaunched  = "browser_launched"
+	EventPageLoaded       = "page_loaded"
+	EventScrolled         = "scrolled"
+	EventArticleExtracted = "article_extracted"
+	EventSaved            = "saved"
+	EventContentCrawled   = "content_crawled"
+	EventCommentsCrawled  = "comments_crawled"
+	EventAccountMetrics   = "account_metrics"
+	EventFinished         = "finished"
+	EventFailed           = "failed"
+)
+
+// Event 爬取任务进度事件
+type Event struct {
+	Seq   int                    `json:"seq"`
+	Type  string                 `json:"type"`
+	JobID string                 `json:"job_id"`
+	Time  time.Time              `json:"time"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+}
+
+// Stream 单个任务的事件流，保留全部历史事件，后加入的订阅方可以从头回放
+type Stream struct {
+	jobID string
+
+	mu      sync.Mutex
+	events  []Event
+	closed  bool
+	updated chan struct{}
+}
+
+// NewStream 创建任务事件流
+func NewStream(jobID string) *Stream {
+	return &Stream{jobID: jobID, updated: make(chan struct{})}
+}
+
+// Publish 发布事件，事件流关闭后忽略
+func (s *Stream) Publish(eventType string, data map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.events = append(s.events, Event{
+		Seq:   len(s.events) + 1,
+		Type:  eventType,
+		JobID: s.jobID,
+		Time:  time.Now(),
+		Data:  data,
+	})
+	s.notifyLocked()
+}
+
+// Close 关闭事件流，并唤醒所有等待方
+func (s *Stream) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	s.notifyLocked()
+}
+
+// Since 返回序号大于 seq 的事件、用于等待后续事件的通道以及是否已关闭
+func (s *Stream) Since(seq int) ([]Event, <-chan struct{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var events []Event
+	if seq < 0 {
+		seq = 0
+	}
+	if seq < len(s.events) {
+		events = append(events, s.events[seq:]...)
+	}
+	return events, s.updated, s.closed
+}
+
+func (s *Stream) notifyLocked() {
+	close(s.updated)
+	s.updated = make(chan struct{})
+}
+
+type contextKey struct{}
+
+// WithStream 将事件流保存到上下文中
+func WithStream(ctx context.Context, s *Stream) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// Report 向上下文中的事件流发布事件，上下文中没有事件流时不做任何事
+func Report(ctx context.Context, eventType string, data map[string]interface{}) {
+	if s, ok := ctx.Value(contextKey{}).(*Stream); ok {
+		s.Publish(eventType, data)
+	}
+}
@@ -0,0 +1,231 @@
+package proxy
+
+import (
+	"context"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/metrics"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrNoProxy 配置了代理但当前没有可用的代理
+var ErrNoProxy = errcode.New(errcode.ProxyUnavailable, "没有可用的代理")
+
+const (
+	defaultCheckInterval = time.Minute
+	defaultCheckTimeout  = 10 * time.Second
+)
+
+// Proxy 单个代理
+type Proxy struct {
+	Server   string // scheme://host:port，不含认证信息
+	Username string
+	Password string
+}
+
+// String 不含认证信息的代理地址，可用于日志和任务记录
+func (p *Proxy) String() string {
+	return p.Server
+}
+
+// URL 带认证信息的代理地址
+func (p *Proxy) URL() *url.URL {
+	u, _ := url.Parse(p.Server)
+	if p.Username != "" {
+		u.User = url.UserPassword(p.Username, p.Password)
+	}
+	return u
+}
+
+// HTTPClient 返回经过代理访问的 HTTP 客户端，p 为 nil 时直接访问
+func HTTPClient(p *Proxy, timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if p != nil {
+		transport.Proxy = http.ProxyURL(p.URL())
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}
+}
+
+// Pool 代理池，同一账号固定使用同一个代理，健康检查失败的代理暂停分配
+type Pool interface {
+	// Pick 返回账号使用的代理，未配置代理时返回 nil
+	Pick(account string) (*Proxy, error)
+	// Close 停止健康检查
+	Close()
+}
+
+type entry struct {
+	proxy   *Proxy
+	healthy bool
+}
+
+type pool struct {
+	log     logger.Logger
+	entries []*entry
+
+	mu     sync.Mutex
+	pinned map[string]*entry // 账号 -> 代理
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewPool(cfg config.ProxyConfig) Pool {
+	p := &pool{
+		log:    logger.FromContext(context.Background()).WithModule(logger.ModuleProxy),
+		pinned: make(map[string]*entry),
+		stop:   make(chan struct{}),
+	}
+	for _, server := range cfg.Servers {
+		px := newProxy(server)
+		p.entries = append(p.entries, &entry{proxy: px, healthy: true})
+		metrics.ProxyHealthy.WithLabelValues(px.String()).Set(1)
+	}
+
+	if len(p.entries) > 0 && cfg.HealthCheck.URL != "" {
+		p.wg.Add(1)
+		go p.healthLoop(cfg.HealthCheck)
+	}
+	return p
+}
+
+// newProxy 解析配置中的代理，认证信息可以写在地址中或单独配置
+func newProxy(server config.ProxyServer) *Proxy {
+	u, _ := url.Parse(server.URL)
+	px := &Proxy{
+		Server:   fmt.Sprintf("%s://%s", u.Scheme, u.Host),
+		Username: server.Username,
+		Password: server.Password,
+	}
+	if u.User != nil && px.Username == "" {
+		px.Username = u.User.Username()
+		px.Password, _ = u.User.Password()
+	}
+	return px
+}
+
+func (p *pool) Pick(account string) (*Proxy, error) {
+	if len(p.entries) == 0 {
+		return nil, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e, ok := p.pinned[account]; ok && e.healthy {
+		return e.proxy, nil
+	}
+
+	// 选择分配账号最少的可用代理
+	counts := make(map[*entry]int, len(p.entries))
+	for _, e := range p.pinned {
+		counts[e]++
+	}
+	var picked *entry
+	for _, e := range p.entries {
+		if e.healthy && (picked == nil || counts[e] < counts[picked]) {
+			picked = e
+		}
+	}
+	if picked == nil {
+		return nil, ErrNoProxy
+	}
+
+	if previous, ok := p.pinned[account]; ok {
+		p.log.Warn("账号的代理不可用，重新分配",
+			"account", account,
+			"previous", previous.proxy.String(),
+			"proxy", picked.proxy.String(),
+		)
+	} else {
+		p.log.Info("为账号分配代理", "account", account, "proxy", picked.proxy.String())
+	}
+	p.pinned[account] = picked
+	return picked.proxy, nil
+}
+
+// healthLoop 定期通过每个代理访问检查地址
+func (p *pool) healthLoop(cfg config.ProxyHealthCheckConfig) {
+	defer p.wg.Done()
+
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultCheckInterval
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		p.checkAll(cfg.URL, timeout)
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *pool) checkAll(checkURL string, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for _, e := range p.entries {
+		wg.Add(1)
+		go func(e *entry) {
+			defer wg.Done()
+			err := check(e.proxy, checkURL, timeout)
+
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			switch {
+			case err != nil && e.healthy:
+				p.log.Warn("代理健康检查失败，暂停分配", "proxy", e.proxy.String(), "error", err)
+			case err == nil && !e.healthy:
+				p.log.Info("代理恢复可用", "proxy", e.proxy.String())
+			}
+			e.healthy = err == nil
+			if e.healthy {
+				metrics.ProxyHealthy.WithLabelValues(e.proxy.String()).Set(1)
+			} else {
+				metrics.ProxyHealthy.WithLabelValues(e.proxy.String()).Set(0)
+			}
+		}(e)
+	}
+	wg.Wait()
+}
+
+// check 通过代理访问检查地址，5xx 或无法连接视为不可用
+func check(px *Proxy, checkURL string, timeout time.Duration) error {
+	client := HTTPClient(px, timeout)
+	defer client.CloseIdleConnections()
+
+	resp, err := client.Get(checkURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (p *pool) Close() {
+	select {
+	case <-p.stop:
+		return
+	default:
+		close(p.stop)
+	}
+	p.wg.Wait()
+}
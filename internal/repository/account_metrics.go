@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AccountMetricsRepository interface {
+	Create(ctx context.Context, snapshot *AccountMetricsSnapshot) error
+	FindByAccount(ctx context.Context, account string, from, to time.Time) ([]AccountMetricsSnapshot, error)
+	UpsertDaily(ctx context.Context, metrics []AccountDailyMetric) error
+	FindDaily(ctx context.Context, account string, from, to time.Time) ([]AccountDailyMetric, error)
+}
+
+type GormAccountMetricsRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAccountMetricsRepository(db *gorm.DB) AccountMetricsRepository {
+	return &GormAccountMetricsRepository{db: db}
+}
+
+func (r *GormAccountMetricsRepository) Create(ctx context.Context, snapshot *AccountMetricsSnapshot) error {
+	return wrapDBError(r.db.WithContext(ctx).Create(snapshot).Error)
+}
+
+// FindByAccount 按抓取时间返回账号在 [from, to) 内的快照，零值表示不限制
+func (r *GormAccountMetricsRepository) FindByAccount(ctx context.Context, account string, from, to time.Time) ([]AccountMetricsSnapshot, error) {
+	query := r.db.WithContext(ctx).Where("account = ?", account)
+	if !from.IsZero() {
+		query = query.Where("captured_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("captured_at < ?", to)
+	}
+
+	var snapshots []AccountMetricsSnapshot
+	if err := query.Order("captured_at ASC").Find(&snapshots).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return snapshots, nil
+}
+
+// UpsertDaily 保存账号每日数据，同一账号同一天已有数据时覆盖
+func (r *GormAccountMetricsRepository) UpsertDaily(ctx context.Context, metrics []AccountDailyMetric) error {
+	if len(metrics) == 0 {
+		return nil
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"view_count", "upvotes", "followers", "crawl_run_id", "updated_at"}),
+	}).Create(&metrics).Error
+	return wrapDBError(err)
+}
+
+// FindDaily 按日期返回账号在 [from, to) 内的每日数据，from 和 to 都按 Asia/Shanghai 时区取日期，零值表示不限制
+func (r *GormAccountMetricsRepository) FindDaily(ctx context.Context, account string, from, to time.Time) ([]AccountDailyMetric, error) {
+	query := r.db.WithContext(ctx).Where("account = ?", account)
+	if !from.IsZero() {
+		query = query.Where("date >= ?", dateOnly(from))
+	}
+	if !to.IsZero() {
+		query = query.Where("date < ?", dateOnly(to))
+	}
+
+	var metrics []AccountDailyMetric
+	if err := query.Order("date ASC").Find(&metrics).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return metrics, nil
+}
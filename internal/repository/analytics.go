@@ -0,0 +1,293 @@
+package repository
+
+import (
+	"context"
+	"crawler/pkg/timezone"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 可用于统计和排序的文章数据
+const (
+	StatReads     = "reads"
+	StatUpvotes   = "upvotes"
+	StatComments  = "comments"
+	StatBookmarks = "bookmarks"
+	StatLikes     = "likes"
+)
+
+// statColumns 统计项对应的列，拼接 SQL 前必须经过此表校验
+var statColumns = map[string]string{
+	StatReads:     "view_count",
+	StatUpvotes:   "upvote",
+	StatComments:  "comments",
+	StatBookmarks: "bookmarks",
+	StatLikes:     "likes",
+}
+
+// ValidStat 判断统计项是否有效
+func ValidStat(stat string) bool {
+	_, ok := statColumns[stat]
+	return ok
+}
+
+// 互动率排序方式
+const (
+	RateUpvote   = "upvote_rate"
+	RateBookmark = "bookmark_rate"
+)
+
+// RankedArticle 按统计项排序的文章，Value 为总数或时间窗口内的增量
+type RankedArticle struct {
+	ID          int64
+	Title       string
+	Link        string
+	PublishedAt *time.Time
+	Value       int
+}
+
+// DailyStats 某一天全部文章（或单篇文章）的统计数据合计，以及与上一个快照日相比的变化，第一天的变化为空
+type DailyStats struct {
+	SnapshotDate   time.Time
+	Reads          int `gorm:"column:view_count"` // reads 是 MySQL 保留字
+	Upvotes        int
+	Comments       int
+	Bookmarks      int
+	Likes          int
+	ReadsDelta     *int `gorm:"column:view_count_delta"`
+	UpvotesDelta   *int
+	CommentsDelta  *int
+	BookmarksDelta *int
+	LikesDelta     *int
+}
+
+// Engagement 文章互动率，阅读数为 0 时比率为空
+type Engagement struct {
+	ID           int64
+	Title        string
+	Link         string
+	Reads        int `gorm:"column:view_count"`
+	Upvotes      int
+	Bookmarks    int
+	UpvoteRate   *float64
+	BookmarkRate *float64
+}
+
+// MonthlyCadence 每月发布的文章数和这些文章当前的数据
+type MonthlyCadence struct {
+	Month    string // 2006-01
+	Articles int
+	Reads    int `gorm:"column:view_count"`
+	Upvotes  int
+}
+
+type AnalyticsRepository interface {
+	SnapshotStats(ctx context.Context, runID string, date time.Time) (int64, error)
+	TopArticles(ctx context.Context, stat string, from, to time.Time, limit int) ([]RankedArticle, error)
+	DailyStats(ctx context.Context, articleID int64, from, to time.Time) ([]DailyStats, error)
+	Engagement(ctx context.Context, order string, minReads, limit int) ([]Engagement, error)
+	EngagementSummary(ctx context.Context, minReads int) (*Engagement, error)
+	PublishingCadence(ctx context.Context, from, to time.Time) ([]MonthlyCadence, error)
+}
+
+type GormAnalyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &GormAnalyticsRepository{db: db}
+}
+
+// SnapshotStats 将全部文章当前的统计数据写入 date 当天的快照，已有快照时覆盖，返回影响的行数。
+// 已删除的文章也保留快照，保证按天合计时前后可比
+func (r *GormAnalyticsRepository) SnapshotStats(ctx context.Context, runID string, date time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO article_stat_snapshots
+			(article_id, snapshot_date, view_count, upvote, comments, bookmarks, likes, crawl_run_id, created_at, updated_at)
+		SELECT id, ?, view_count, upvote, comments, bookmarks, likes, ?, NOW(), NOW()
+		FROM articles
+		ON DUPLICATE KEY UPDATE
+			view_count = VALUES(view_count),
+			upvote = VALUES(upvote),
+			comments = VALUES(comments),
+			bookmarks = VALUES(bookmarks),
+			likes = VALUES(likes),
+			crawl_run_id = VALUES(crawl_run_id),
+			updated_at = VALUES(updated_at)`,
+		dateOnly(date), runID,
+	)
+	if result.Error != nil {
+		return 0, wrapDBError(result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// TopArticles 按统计项返回前 limit 篇正常状态的文章。from 和 to 都为零值时按当前总数排序；
+// 否则按 [from, to] 内的增量排序：窗口内最后一个快照减去 from 之前最后一个快照，
+// from 之前没有快照时，窗口内发布的文章从 0 算起，其他文章从窗口内第一个快照算起。
+// 列是无符号整数，数据下降时直接相减会超出范围，需要先转换为有符号数
+func (r *GormAnalyticsRepository) TopArticles(ctx context.Context, stat string, from, to time.Time, limit int) ([]RankedArticle, error) {
+	column, ok := statColumns[stat]
+	if !ok {
+		return nil, fmt.Errorf("不支持的统计项: %s", stat)
+	}
+
+	var rows []RankedArticle
+	db := r.db.WithContext(ctx)
+	if from.IsZero() && to.IsZero() {
+		err := db.Raw(fmt.Sprintf(`
+			SELECT id, title, link, published_at, %[1]s AS value
+			FROM articles
+			WHERE status = ?
+			ORDER BY %[1]s DESC, id DESC
+			LIMIT ?`, column),
+			ArticleStatusNormal, limit,
+		).Scan(&rows).Error
+		return rows, wrapDBError(err)
+	}
+
+	start, end := dateRange(from, to)
+	err := db.Raw(fmt.Sprintf(`
+		SELECT a.id, a.title, a.link, a.published_at,
+			CAST(e.%[1]s AS SIGNED) - CAST(COALESCE(b.%[1]s, IF(a.published_at >= ?, 0, f.%[1]s)) AS SIGNED) AS value
+		FROM articles a
+		JOIN article_stat_snapshots e ON e.article_id = a.id AND e.snapshot_date = (
+			SELECT MAX(snapshot_date) FROM article_stat_snapshots
+			WHERE article_id = a.id AND snapshot_date BETWEEN ? AND ?)
+		JOIN article_stat_snapshots f ON f.article_id = a.id AND f.snapshot_date = (
+			SELECT MIN(snapshot_date) FROM article_stat_snapshots
+			WHERE article_id = a.id AND snapshot_date BETWEEN ? AND ?)
+		LEFT JOIN article_stat_snapshots b ON b.article_id = a.id AND b.snapshot_date = (
+			SELECT MAX(snapshot_date) FROM article_stat_snapshots
+			WHERE article_id = a.id AND snapshot_date < ?)
+		WHERE a.status = ?
+		ORDER BY value DESC, a.id DESC
+		LIMIT ?`, column),
+		start, start, end, start, end, start, ArticleStatusNormal, limit,
+	).Scan(&rows).Error
+	return rows, wrapDBError(err)
+}
+
+// DailyStats 按快照日期返回 [from, to] 内的统计数据合计和与上一个快照日相比的变化，
+// articleID 不为 0 时只统计该文章
+func (r *GormAnalyticsRepository) DailyStats(ctx context.Context, articleID int64, from, to time.Time) ([]DailyStats, error) {
+	filter, args := "", []interface{}{}
+	if articleID != 0 {
+		filter, args = "WHERE article_id = ?", []interface{}{articleID}
+	}
+	totals := `
+		SELECT snapshot_date,
+			SUM(view_count) AS view_count, SUM(upvote) AS upvotes, SUM(comments) AS comments,
+			SUM(bookmarks) AS bookmarks, SUM(likes) AS likes
+		FROM article_stat_snapshots ` + filter + `
+		GROUP BY snapshot_date`
+	previous := "snapshot_date < cur.snapshot_date"
+	if articleID != 0 {
+		previous += " AND article_id = ?"
+	}
+
+	start, end := dateRange(from, to)
+	query := `
+		SELECT cur.snapshot_date, cur.view_count, cur.upvotes, cur.comments, cur.bookmarks, cur.likes,
+			cur.view_count - prev.view_count AS view_count_delta,
+			cur.upvotes - prev.upvotes AS upvotes_delta,
+			cur.comments - prev.comments AS comments_delta,
+			cur.bookmarks - prev.bookmarks AS bookmarks_delta,
+			cur.likes - prev.likes AS likes_delta
+		FROM (` + totals + `) cur
+		LEFT JOIN (` + totals + `) prev ON prev.snapshot_date = (
+			SELECT MAX(snapshot_date) FROM article_stat_snapshots WHERE ` + previous + `)
+		WHERE cur.snapshot_date BETWEEN ? AND ?
+		ORDER BY cur.snapshot_date`
+	params := append(append([]interface{}{}, args...), args...)
+	params = append(append(params, args...), start, end)
+
+	var rows []DailyStats
+	if err := r.db.WithContext(ctx).Raw(query, params...).Scan(&rows).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return rows, nil
+}
+
+// Engagement 返回阅读数不少于 minReads 的正常状态文章的点赞率和收藏率，按 order 指定的比率排序
+func (r *GormAnalyticsRepository) Engagement(ctx context.Context, order string, minReads, limit int) ([]Engagement, error) {
+	if order != RateUpvote && order != RateBookmark {
+		return nil, fmt.Errorf("不支持的排序方式: %s", order)
+	}
+
+	var rows []Engagement
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT id, title, link, view_count, upvote AS upvotes, bookmarks,
+			upvote / NULLIF(view_count, 0) AS upvote_rate,
+			bookmarks / NULLIF(view_count, 0) AS bookmark_rate
+		FROM articles
+		WHERE status = ? AND view_count >= ?
+		ORDER BY `+order+` DESC, id DESC
+		LIMIT ?`,
+		ArticleStatusNormal, minReads, limit,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+	return rows, nil
+}
+
+// EngagementSummary 阅读数不少于 minReads 的正常状态文章合计的点赞率和收藏率
+func (r *GormAnalyticsRepository) EngagementSummary(ctx context.Context, minReads int) (*Engagement, error) {
+	var summary Engagement
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(SUM(view_count), 0) AS view_count,
+			COALESCE(SUM(upvote), 0) AS upvotes,
+			COALESCE(SUM(bookmarks), 0) AS bookmarks,
+			SUM(upvote) / NULLIF(SUM(view_count), 0) AS upvote_rate,
+			SUM(bookmarks) / NULLIF(SUM(view_count), 0) AS bookmark_rate
+		FROM articles
+		WHERE status = ? AND view_count >= ?`,
+		ArticleStatusNormal, minReads,
+	).Scan(&summary).Error
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+	return &summary, nil
+}
+
+// PublishingCadence 按发布月份统计 [from, to) 内发布的文章数，没有发布时间的文章不统计，零值表示不限制
+func (r *GormAnalyticsRepository) PublishingCadence(ctx context.Context, from, to time.Time) ([]MonthlyCadence, error) {
+	query := r.db.WithContext(ctx).Model(&Article{}).
+		Select("DATE_FORMAT(published_at, '%Y-%m') AS month, COUNT(*) AS articles, " +
+			"COALESCE(SUM(view_count), 0) AS view_count, COALESCE(SUM(upvote), 0) AS upvotes").
+		Where("published_at IS NOT NULL")
+	if !from.IsZero() {
+		query = query.Where("published_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("published_at < ?", to)
+	}
+
+	var rows []MonthlyCadence
+	if err := query.Group("month").Order("month").Scan(&rows).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return rows, nil
+}
+
+// dateRange 将可选的起止时间转换为快照日期范围，零值表示不限制
+func dateRange(from, to time.Time) (time.Time, time.Time) {
+	start := time.Date(1970, 1, 1, 0, 0, 0, 0, timezone.Shanghai)
+	end := time.Date(9999, 12, 31, 0, 0, 0, 0, timezone.Shanghai)
+	if !from.IsZero() {
+		start = dateOnly(from)
+	}
+	if !to.IsZero() {
+		end = dateOnly(to)
+	}
+	return start, end
+}
+
+// dateOnly 返回 Asia/Shanghai 时区当天零点，与抓取时的日期划分一致
+func dateOnly(t time.Time) time.Time {
+	t = t.In(timezone.Shanghai)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, timezone.Shanghai)
+}
@@ -0,0 +1,385 @@
+package repository
+
+import (
+	"context"
+	"crawler/pkg/timezone"
+	"fmt"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB 连接 TEST_MYSQL_DSN 指定的测试库并清空分析相关的表，未设置时跳过测试。
+// DSN 需要与服务使用相同的时区参数，例如
+// user:pass@tcp(127.0.0.1:3306)/crawler_test?parseTime=True&loc=Asia%2FShanghai&time_zone=%27%2B08%3A00%27
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_MYSQL_DSN")
+	if dsn == "" {
+		t.Skip("未设置 TEST_MYSQL_DSN，跳过数据库测试")
+	}
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("连接测试数据库失败: %v", err)
+	}
+	if err := db.AutoMigrate(&Article{}, &ArticleStatSnapshot{}); err != nil {
+		t.Fatalf("迁移测试数据库失败: %v", err)
+	}
+	for _, table := range []string{"article_stat_snapshots", "articles"} {
+		if err := db.Exec("DELETE FROM " + table).Error; err != nil {
+			t.Fatalf("清空 %s 失败: %v", table, err)
+		}
+	}
+	return db
+}
+
+// shanghai 返回 Asia/Shanghai 时区的时间
+func shanghai(year int, month time.Month, day, hour, min int) time.Time {
+	return time.Date(year, month, day, hour, min, 0, 0, timezone.Shanghai)
+}
+
+// 测试数据中的文章
+type seededArticles struct {
+	old, growing, deleted, fresh, unread int64
+}
+
+// seedAnalytics 写入 11-30、12-01、12-03 三天的快照，每天包含当时已存在的全部文章：
+//   - old：10 月发布，赞同从 10 降到 8
+//   - growing：11 月发布，赞同 30 → 35 → 50
+//   - deleted：11 月发布后被删除，不参与排行和互动率
+//   - fresh：北京时间 12-01 00:30 发布（UTC 仍是 11-30），只有 12-03 的快照
+//   - unread：12-03 发布，阅读数为 0
+func seedAnalytics(t *testing.T, db *gorm.DB) seededArticles {
+	t.Helper()
+	type snapshot struct {
+		date                      time.Time
+		reads, upvotes, bookmarks int
+	}
+	day1, day2, day3 := shanghai(2024, 11, 30, 0, 0), shanghai(2024, 12, 1, 0, 0), shanghai(2024, 12, 3, 0, 0)
+	seeds := []struct {
+		link        string
+		publishedAt time.Time
+		status      int8
+		snapshots   []snapshot
+	}{
+		{"/p/old", shanghai(2024, 10, 1, 9, 0), ArticleStatusNormal,
+			[]snapshot{{day1, 100, 10, 0}, {day2, 100, 10, 0}, {day3, 100, 8, 0}}},
+		{"/p/growing", shanghai(2024, 11, 20, 10, 0), ArticleStatusNormal,
+			[]snapshot{{day1, 600, 30, 10}, {day2, 800, 35, 15}, {day3, 1000, 50, 20}}},
+		{"/p/deleted", shanghai(2024, 11, 1, 8, 0), ArticleStatusDeleted,
+			[]snapshot{{day1, 5000, 100, 0}, {day2, 5000, 100, 0}, {day3, 5000, 100, 0}}},
+		{"/p/fresh", shanghai(2024, 12, 1, 0, 30), ArticleStatusNormal,
+			[]snapshot{{day3, 400, 40, 4}}},
+		{"/p/unread", shanghai(2024, 12, 3, 9, 0), ArticleStatusNormal,
+			[]snapshot{{day3, 0, 0, 0}}},
+	}
+
+	ids := make([]int64, len(seeds))
+	for i, seed := range seeds {
+		last := seed.snapshots[len(seed.snapshots)-1]
+		publishedAt := seed.publishedAt
+		article := Article{
+			Title:       seed.link,
+			Link:        seed.link,
+			PublishedAt: &publishedAt,
+			ViewCount:   last.reads,
+			Upvote:      last.upvotes,
+			Bookmarks:   last.bookmarks,
+			Status:      seed.status,
+		}
+		if err := db.Create(&article).Error; err != nil {
+			t.Fatalf("写入文章失败: %v", err)
+		}
+		ids[i] = article.ID
+		for _, s := range seed.snapshots {
+			row := ArticleStatSnapshot{
+				ArticleID:    article.ID,
+				SnapshotDate: s.date,
+				ViewCount:    s.reads,
+				Upvote:       s.upvotes,
+				Bookmarks:    s.bookmarks,
+				CrawlRunID:   "test",
+			}
+			if err := db.Create(&row).Error; err != nil {
+				t.Fatalf("写入快照失败: %v", err)
+			}
+		}
+	}
+	return seededArticles{old: ids[0], growing: ids[1], deleted: ids[2], fresh: ids[3], unread: ids[4]}
+}
+
+func TestTopArticles(t *testing.T) {
+	db := openTestDB(t)
+	a := seedAnalytics(t, db)
+	repo := NewGormAnalyticsRepository(db)
+	ctx := context.Background()
+
+	type ranked struct {
+		id    int64
+		value int
+	}
+	tests := []struct {
+		name     string
+		from, to time.Time
+		limit    int
+		want     []ranked
+	}{
+		{"按当前总数", time.Time{}, time.Time{}, 10,
+			[]ranked{{a.growing, 50}, {a.fresh, 40}, {a.old, 8}, {a.unread, 0}}},
+		{"窗口内增量", shanghai(2024, 12, 1, 0, 0), shanghai(2024, 12, 3, 0, 0), 10,
+			[]ranked{{a.fresh, 40}, {a.growing, 20}, {a.unread, 0}, {a.old, -2}}},
+		// UTC 12-02 16:30 是北京时间 12-03，窗口应包含 12-03 的快照
+		{"按北京时间划分日期", time.Date(2024, 11, 30, 16, 0, 0, 0, time.UTC), time.Date(2024, 12, 2, 16, 30, 0, 0, time.UTC), 10,
+			[]ranked{{a.fresh, 40}, {a.growing, 20}, {a.unread, 0}, {a.old, -2}}},
+		{"窗口之前没有快照时从窗口内第一个快照算起", shanghai(2024, 11, 30, 0, 0), shanghai(2024, 12, 1, 0, 0), 10,
+			[]ranked{{a.growing, 5}, {a.old, 0}}},
+		{"限制数量", shanghai(2024, 12, 1, 0, 0), time.Time{}, 2,
+			[]ranked{{a.fresh, 40}, {a.growing, 20}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := repo.TopArticles(ctx, StatUpvotes, tt.from, tt.to, tt.limit)
+			if err != nil {
+				t.Fatalf("TopArticles 返回错误: %v", err)
+			}
+			got := make([]ranked, len(rows))
+			for i, row := range rows {
+				got[i] = ranked{row.ID, row.Value}
+			}
+			if !equalSlices(got, tt.want) {
+				t.Errorf("TopArticles = %v，期望 %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyStats(t *testing.T) {
+	db := openTestDB(t)
+	a := seedAnalytics(t, db)
+	repo := NewGormAnalyticsRepository(db)
+	ctx := context.Background()
+
+	type daily struct {
+		date                     string
+		reads, upvotes           int
+		readsDelta, upvotesDelta *int
+	}
+	tests := []struct {
+		name      string
+		articleID int64
+		from, to  time.Time
+		want      []daily
+	}{
+		{"全部快照", 0, time.Time{}, time.Time{}, []daily{
+			{"2024-11-30", 5700, 140, nil, nil},
+			{"2024-12-01", 5900, 145, intPtr(200), intPtr(5)},
+			{"2024-12-03", 6500, 198, intPtr(600), intPtr(53)},
+		}},
+		// 窗口内第一天的变化仍与窗口之前的快照比较
+		{"日期范围", 0, shanghai(2024, 12, 1, 0, 0), shanghai(2024, 12, 3, 0, 0), []daily{
+			{"2024-12-01", 5900, 145, intPtr(200), intPtr(5)},
+			{"2024-12-03", 6500, 198, intPtr(600), intPtr(53)},
+		}},
+		{"按北京时间划分日期", 0, time.Date(2024, 12, 2, 16, 30, 0, 0, time.UTC), time.Time{}, []daily{
+			{"2024-12-03", 6500, 198, intPtr(600), intPtr(53)},
+		}},
+		{"单篇文章", a.growing, time.Time{}, time.Time{}, []daily{
+			{"2024-11-30", 600, 30, nil, nil},
+			{"2024-12-01", 800, 35, intPtr(200), intPtr(5)},
+			{"2024-12-03", 1000, 50, intPtr(200), intPtr(15)},
+		}},
+		{"单篇文章只与自己的上一个快照比较", a.fresh, time.Time{}, time.Time{}, []daily{
+			{"2024-12-03", 400, 40, nil, nil},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := repo.DailyStats(ctx, tt.articleID, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("DailyStats 返回错误: %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("DailyStats 返回 %d 天，期望 %d 天: %+v", len(rows), len(tt.want), rows)
+			}
+			for i, row := range rows {
+				want := tt.want[i]
+				date := row.SnapshotDate.In(timezone.Shanghai).Format(time.DateOnly)
+				if date != want.date || row.Reads != want.reads || row.Upvotes != want.upvotes ||
+					!equalIntPtr(row.ReadsDelta, want.readsDelta) || !equalIntPtr(row.UpvotesDelta, want.upvotesDelta) {
+					t.Errorf("第 %d 天 = {%s %d %d %s %s}，期望 {%s %d %d %s %s}", i,
+						date, row.Reads, row.Upvotes, formatIntPtr(row.ReadsDelta), formatIntPtr(row.UpvotesDelta),
+						want.date, want.reads, want.upvotes, formatIntPtr(want.readsDelta), formatIntPtr(want.upvotesDelta))
+				}
+			}
+		})
+	}
+}
+
+func TestSnapshotStatsUsesShanghaiDate(t *testing.T) {
+	db := openTestDB(t)
+	seedAnalytics(t, db)
+	repo := NewGormAnalyticsRepository(db)
+
+	// UTC 12-03 16:30 已是北京时间 12-04
+	if _, err := repo.SnapshotStats(context.Background(), "run", time.Date(2024, 12, 3, 16, 30, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("SnapshotStats 返回错误: %v", err)
+	}
+	var count int64
+	db.Model(&ArticleStatSnapshot{}).Where("snapshot_date = ?", "2024-12-04").Count(&count)
+	if count != 5 {
+		t.Errorf("2024-12-04 的快照数 = %d，期望 5", count)
+	}
+}
+
+func TestEngagement(t *testing.T) {
+	db := openTestDB(t)
+	a := seedAnalytics(t, db)
+	repo := NewGormAnalyticsRepository(db)
+	ctx := context.Background()
+
+	type rate struct {
+		id           int64
+		upvoteRate   *float64
+		bookmarkRate *float64
+	}
+	tests := []struct {
+		name     string
+		order    string
+		minReads int
+		want     []rate
+	}{
+		{"按点赞率", RateUpvote, 0, []rate{
+			{a.fresh, floatPtr(0.1), floatPtr(0.01)},
+			{a.old, floatPtr(0.08), floatPtr(0)},
+			{a.growing, floatPtr(0.05), floatPtr(0.02)},
+			{a.unread, nil, nil},
+		}},
+		{"按收藏率", RateBookmark, 0, []rate{
+			{a.growing, floatPtr(0.05), floatPtr(0.02)},
+			{a.fresh, floatPtr(0.1), floatPtr(0.01)},
+			{a.old, floatPtr(0.08), floatPtr(0)},
+			{a.unread, nil, nil},
+		}},
+		{"最少阅读数", RateUpvote, 200, []rate{
+			{a.fresh, floatPtr(0.1), floatPtr(0.01)},
+			{a.growing, floatPtr(0.05), floatPtr(0.02)},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := repo.Engagement(ctx, tt.order, tt.minReads, 10)
+			if err != nil {
+				t.Fatalf("Engagement 返回错误: %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("Engagement 返回 %d 篇，期望 %d 篇: %+v", len(rows), len(tt.want), rows)
+			}
+			for i, row := range rows {
+				want := tt.want[i]
+				if row.ID != want.id || !equalRate(row.UpvoteRate, want.upvoteRate) || !equalRate(row.BookmarkRate, want.bookmarkRate) {
+					t.Errorf("第 %d 篇 = {%d %s %s}，期望 {%d %s %s}", i,
+						row.ID, formatRate(row.UpvoteRate), formatRate(row.BookmarkRate),
+						want.id, formatRate(want.upvoteRate), formatRate(want.bookmarkRate))
+				}
+			}
+		})
+	}
+
+	summary, err := repo.EngagementSummary(ctx, 0)
+	if err != nil {
+		t.Fatalf("EngagementSummary 返回错误: %v", err)
+	}
+	if summary.Reads != 1500 || summary.Upvotes != 98 || summary.Bookmarks != 24 ||
+		!equalRate(summary.UpvoteRate, floatPtr(98.0/1500)) || !equalRate(summary.BookmarkRate, floatPtr(24.0/1500)) {
+		t.Errorf("整体互动率 = %+v", summary)
+	}
+
+	empty, err := repo.EngagementSummary(ctx, 1000000)
+	if err != nil {
+		t.Fatalf("EngagementSummary 返回错误: %v", err)
+	}
+	if empty.Reads != 0 || empty.UpvoteRate != nil || empty.BookmarkRate != nil {
+		t.Errorf("没有文章时的整体互动率 = %+v，期望比率为空", empty)
+	}
+}
+
+func TestPublishingCadence(t *testing.T) {
+	db := openTestDB(t)
+	seedAnalytics(t, db)
+	repo := NewGormAnalyticsRepository(db)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []MonthlyCadence
+	}{
+		// fresh 在北京时间 12-01 00:30 发布，应计入 12 月
+		{"全部月份", time.Time{}, time.Time{}, []MonthlyCadence{
+			{Month: "2024-10", Articles: 1, Reads: 100, Upvotes: 8},
+			{Month: "2024-11", Articles: 2, Reads: 6000, Upvotes: 150},
+			{Month: "2024-12", Articles: 2, Reads: 400, Upvotes: 40},
+		}},
+		{"不包含结束时间", shanghai(2024, 11, 1, 0, 0), shanghai(2024, 12, 1, 0, 0), []MonthlyCadence{
+			{Month: "2024-11", Articles: 2, Reads: 6000, Upvotes: 150},
+		}},
+		{"包含开始时间", shanghai(2024, 12, 1, 0, 30), time.Time{}, []MonthlyCadence{
+			{Month: "2024-12", Articles: 2, Reads: 400, Upvotes: 40},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := repo.PublishingCadence(ctx, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("PublishingCadence 返回错误: %v", err)
+			}
+			if !equalSlices(rows, tt.want) {
+				t.Errorf("PublishingCadence = %+v，期望 %+v", rows, tt.want)
+			}
+		})
+	}
+}
+
+func equalSlices[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func intPtr(v int) *int { return &v }
+
+func floatPtr(v float64) *float64 { return &v }
+
+func equalIntPtr(a, b *int) bool {
+	return a == nil && b == nil || a != nil && b != nil && *a == *b
+}
+
+// equalRate 比较比率，MySQL 除法默认保留 4 位小数
+func equalRate(a, b *float64) bool {
+	return a == nil && b == nil || a != nil && b != nil && math.Abs(*a-*b) < 1e-4
+}
+
+func formatIntPtr(v *int) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprint(*v)
+}
+
+func formatRate(v *float64) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%.4f", *v)
+}
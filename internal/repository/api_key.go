@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	FindAll(ctx context.Context) ([]APIKey, error)
+	FindByHash(ctx context.Context, hash string) (*APIKey, error)
+	Delete(ctx context.Context, id int64) (bool, error)
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
+
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &GormAPIKeyRepository{db: db}
+}
+
+func (r *GormAPIKeyRepository) Create(ctx context.Context, key *APIKey) error {
+	return wrapDBError(r.db.WithContext(ctx).Create(key).Error)
+}
+
+func (r *GormAPIKeyRepository) FindAll(ctx context.Context) ([]APIKey, error) {
+	var keys []APIKey
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return keys, nil
+}
+
+// FindByHash 根据密钥哈希查找，不存在时返回 gorm.ErrRecordNotFound
+func (r *GormAPIKeyRepository) FindByHash(ctx context.Context, hash string) (*APIKey, error) {
+	var key APIKey
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", hash).First(&key).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return &key, nil
+}
+
+// Delete 删除密钥，返回是否确实删除了记录
+func (r *GormAPIKeyRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&APIKey{}, id)
+	if result.Error != nil {
+		return false, wrapDBError(result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *GormAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	return wrapDBError(r.db.WithContext(ctx).Model(&APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error)
+}
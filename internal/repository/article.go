@@ -1,16 +1,35 @@
 package repository
 
 import (
+	"context"
 	"crawler/internal/scraper"
 	"crawler/pkg/logger"
+	"crawler/pkg/metrics"
+	"crawler/pkg/tracing"
+	"math"
+	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
 type ArticleRepository interface {
-	UpsertArticles(articles []scraper.ArticleCard) error
+	UpsertArticles(ctx context.Context, articles []scraper.ArticleCard) error
 	FindAll() ([]scraper.ArticleCard, error)
+	ExistingLinks(ctx context.Context, links []string) (map[string]bool, error)
+	SyncStatus(ctx context.Context, links []string, complete bool, maxMissingRatio float64) (*StatusChanges, error)
+	FindByID(ctx context.Context, id int64) (*Article, error)
+	FindForExport(ctx context.Context, ids []int64, includeDeleted bool) ([]Article, error)
+}
+
+// StatusChanges 一次爬取后文章状态的变化
+type StatusChanges struct {
+	Deleted  []string // 本次标记为删除的文章链接
+	Restored []string // 本次恢复的文章链接
+	Missing  int      // 完整爬取时没有出现的正常文章数
+	Skipped  bool     // 没有出现的文章过多，疑似列表没有加载完整，跳过了删除标记
 }
 
 type GormArticleRepository struct {
@@ -21,8 +40,17 @@ func NewGormArticleRepository(db *gorm.DB) ArticleRepository {
 	return &GormArticleRepository{db: db}
 }
 
-func (r *GormArticleRepository) UpsertArticles(articles []scraper.ArticleCard) error {
+func (r *GormArticleRepository) UpsertArticles(ctx context.Context, articles []scraper.ArticleCard) (err error) {
+	ctx, span := tracing.Start(ctx, "db.upsert_articles",
+		semconv.DBSystemMySQL,
+		semconv.DBCollectionName("articles"),
+		attribute.Int("db.batch_size", len(articles)),
+	)
+	defer func() { tracing.End(span, err) }()
+	log := logger.FromContext(ctx).WithModule(logger.ModuleRepository)
+
 	// 将爬虫数据转换为数据库模型
+	now := time.Now()
 	var models []Article
 	for _, article := range articles {
 		models = append(models, Article{
@@ -30,34 +58,45 @@ func (r *GormArticleRepository) UpsertArticles(articles []scraper.ArticleCard) e
 			Link:          article.Link,
 			Description:   article.Description,
 			PublishedTime: article.PublishedTime,
+			PublishedAt:   timePtr(article.PublishedAt),
 			ViewCount:     article.Stats.Reads,
 			Upvote:        article.Stats.Upvote,
 			Comments:      article.Stats.Comments,
 			Bookmarks:     article.Stats.Bookmarks,
 			Likes:         article.Stats.Likes,
-			Status:        1,
+			Status:        ArticleStatusNormal,
+			LastSeenAt:    &now,
 		})
 	}
 
+	log.Debug("开始批量写入文章", "count", len(models))
+
 	// 使用 Upsert 进行批量插入或更新
-	result := r.db.Clauses(clause.OnConflict{
+	start := time.Now()
+	// 已有的发布时间不覆盖，相对时间越早解析越准确
+	updates := append(
+		clause.AssignmentColumns([]string{"view_count", "upvote", "comments", "bookmarks", "likes", "last_seen_at"}),
+		clause.Assignment{Column: clause.Column{Name: "published_at"}, Value: gorm.Expr("COALESCE(published_at, VALUES(published_at))")},
+	)
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "link"}},
-		DoUpdates: clause.AssignmentColumns([]string{"view_count", "upvote", "comments", "bookmarks", "likes"}),
+		DoUpdates: updates,
 	}).Create(&models)
+	metrics.DBUpsertDuration.WithLabelValues(metrics.Outcome(result.Error)).Observe(time.Since(start).Seconds())
 
 	if result.Error != nil {
-		logger.Error("保存文章失败", "error", result.Error)
-		return result.Error
+		log.Error("保存文章失败", "error", result.Error)
+		return wrapDBError(result.Error)
 	}
 
-	logger.Info("成功保存文章", "count", len(articles))
+	log.Info("成功保存文章", "count", len(articles))
 	return nil
 }
 
 func (r *GormArticleRepository) FindAll() ([]scraper.ArticleCard, error) {
 	var articles []Article
 	if err := r.db.Order("created_at DESC").Find(&articles).Error; err != nil {
-		return nil, err
+		return nil, wrapDBError(err)
 	}
 
 	// 转换为爬虫数据结构
@@ -68,6 +107,7 @@ func (r *GormArticleRepository) FindAll() ([]scraper.ArticleCard, error) {
 			Link:          article.Link,
 			Description:   article.Description,
 			PublishedTime: article.PublishedTime,
+			PublishedAt:   derefTime(article.PublishedAt),
 			Stats: scraper.ArticleStats{
 				Reads:     article.ViewCount,
 				Upvote:    article.Upvote,
@@ -80,3 +120,140 @@ func (r *GormArticleRepository) FindAll() ([]scraper.ArticleCard, error) {
 
 	return result, nil
 }
+
+// FindByID 根据文章ID查找，不存在时返回 gorm.ErrRecordNotFound
+func (r *GormArticleRepository) FindByID(ctx context.Context, id int64) (*Article, error) {
+	var article Article
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&article).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return &article, nil
+}
+
+// FindForExport 按发布时间返回要导出的文章，ids 为空时返回全部，默认不含已删除的文章
+func (r *GormArticleRepository) FindForExport(ctx context.Context, ids []int64, includeDeleted bool) ([]Article, error) {
+	query := r.db.WithContext(ctx)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	if !includeDeleted {
+		query = query.Where("status = ?", ArticleStatusNormal)
+	}
+
+	var articles []Article
+	if err := query.Order("published_at ASC, id ASC").Find(&articles).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return articles, nil
+}
+
+// ExistingLinks 返回 links 中已保存的文章链接
+func (r *GormArticleRepository) ExistingLinks(ctx context.Context, links []string) (map[string]bool, error) {
+	existing := make(map[string]bool, len(links))
+	if len(links) == 0 {
+		return existing, nil
+	}
+
+	var found []string
+	if err := r.db.WithContext(ctx).Model(&Article{}).Where("link IN ?", links).Pluck("link", &found).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	for _, link := range found {
+		existing[link] = true
+	}
+	return existing, nil
+}
+
+// SyncStatus 恢复本次出现的已删除文章；complete 为 true（完整爬取）时，
+// 将未出现在本次结果中的正常文章标记为删除。没有出现的文章过多时（见 tooManyMissing），
+// 认为列表没有加载完整（如页面加载缓慢导致提前判断到底），不标记删除
+func (r *GormArticleRepository) SyncStatus(ctx context.Context, links []string, complete bool, maxMissingRatio float64) (_ *StatusChanges, err error) {
+	ctx, span := tracing.Start(ctx, "db.sync_article_status",
+		semconv.DBSystemMySQL,
+		semconv.DBCollectionName("articles"),
+		attribute.Bool("crawler.complete", complete),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	changes := &StatusChanges{}
+	if len(links) == 0 {
+		return changes, nil
+	}
+
+	now := time.Now()
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&Article{}).
+			Where("link IN ? AND status = ?", links, ArticleStatusDeleted).
+			Pluck("link", &changes.Restored).Error; err != nil {
+			return err
+		}
+		if len(changes.Restored) > 0 {
+			if err := tx.Model(&Article{}).Where("link IN ?", changes.Restored).Updates(map[string]interface{}{
+				"status":     ArticleStatusNormal,
+				"deleted_at": nil,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		if !complete {
+			return nil
+		}
+		var missing []string
+		if err := tx.Model(&Article{}).
+			Where("link NOT IN ? AND status = ?", links, ArticleStatusNormal).
+			Pluck("link", &missing).Error; err != nil {
+			return err
+		}
+		changes.Missing = len(missing)
+		if len(missing) == 0 {
+			return nil
+		}
+
+		var normal int64
+		if err := tx.Model(&Article{}).Where("status = ?", ArticleStatusNormal).Count(&normal).Error; err != nil {
+			return err
+		}
+		if tooManyMissing(len(missing), int(normal), maxMissingRatio) {
+			changes.Skipped = true
+			return nil
+		}
+
+		changes.Deleted = missing
+		return tx.Model(&Article{}).Where("link IN ?", changes.Deleted).Updates(map[string]interface{}{
+			"status":     ArticleStatusDeleted,
+			"deleted_at": now,
+		}).Error
+	})
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+
+	span.SetAttributes(
+		attribute.Int("crawler.deleted", len(changes.Deleted)),
+		attribute.Int("crawler.restored", len(changes.Restored)),
+	)
+	return changes, nil
+}
+
+// tooManyMissing 判断没有出现的文章是否超过正常文章的 ratio，文章较少时至少允许 1 篇被删除，ratio 为 0 时不检查
+func tooManyMissing(missing, normal int, ratio float64) bool {
+	if ratio <= 0 {
+		return false
+	}
+	return float64(missing) > math.Max(1, float64(normal)*ratio)
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func derefTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
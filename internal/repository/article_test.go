@@ -0,0 +1,27 @@
+package repository
+
+import "testing"
+
+func TestTooManyMissing(t *testing.T) {
+	tests := []struct {
+		missing, normal int
+		ratio           float64
+		want            bool
+	}{
+		// 文章较少时至少允许删除 1 篇
+		{1, 3, 0.1, false},
+		{2, 3, 0.1, true},
+		{1, 9, 0.1, false},
+		{10, 100, 0.1, false},
+		{11, 100, 0.1, true},
+		{30, 100, 0.5, false},
+		// 0 表示不检查
+		{100, 100, 0, false},
+		{1, 0, 0.1, false},
+	}
+	for _, tt := range tests {
+		if got := tooManyMissing(tt.missing, tt.normal, tt.ratio); got != tt.want {
+			t.Errorf("tooManyMissing(%d, %d, %v) = %v，期望 %v", tt.missing, tt.normal, tt.ratio, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"crawler/internal/scraper"
+	"crawler/pkg/tracing"
+	"database/sql"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommentRepository interface {
+	UpsertComments(ctx context.Context, articleID int64, comments []scraper.Comment, runID string) error
+	FindByArticle(ctx context.Context, articleID int64) ([]Comment, error)
+	Targets(ctx context.Context, links []string, limit int, retryAfter time.Duration) ([]CommentTarget, error)
+	ReplyCounts(ctx context.Context, articleID int64) (map[string]int, error)
+	LatestRootCommentAt(ctx context.Context, articleID int64) (time.Time, error)
+	MarkChecked(ctx context.Context, articleID int64) error
+}
+
+// CommentTarget 需要抓取评论的文章
+type CommentTarget struct {
+	ArticleID int64
+	Link      string
+	Stored    int // 已保存的评论数（含回复）
+}
+
+type GormCommentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCommentRepository(db *gorm.DB) CommentRepository {
+	return &GormCommentRepository{db: db}
+}
+
+// UpsertComments 按知乎评论ID批量插入或更新评论，已有评论更新内容、点赞数和回复数
+func (r *GormCommentRepository) UpsertComments(ctx context.Context, articleID int64, comments []scraper.Comment, runID string) (err error) {
+	ctx, span := tracing.Start(ctx, "db.upsert_comments",
+		semconv.DBSystemMySQL,
+		semconv.DBCollectionName("comments"),
+		attribute.Int("db.batch_size", len(comments)),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if len(comments) == 0 {
+		return nil
+	}
+
+	models := make([]Comment, 0, len(comments))
+	for _, comment := range comments {
+		models = append(models, Comment{
+			ArticleID:       articleID,
+			CommentID:       comment.ID,
+			RootCommentID:   comment.RootID,
+			ParentCommentID: comment.ParentID,
+			AuthorName:      comment.AuthorName,
+			AuthorToken:     comment.AuthorToken,
+			Content:         comment.Content,
+			Likes:           comment.Likes,
+			ReplyCount:      comment.ReplyCount,
+			CommentedAt:     comment.CreatedAt,
+			CrawlRunID:      runID,
+		})
+	}
+
+	err = r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "comment_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"content", "likes", "reply_count", "crawl_run_id", "updated_at"}),
+	}).CreateInBatches(&models, 200).Error
+	return wrapDBError(err)
+}
+
+// FindByArticle 按评论时间返回文章的全部评论和回复
+func (r *GormCommentRepository) FindByArticle(ctx context.Context, articleID int64) ([]Comment, error) {
+	var comments []Comment
+	err := r.db.WithContext(ctx).
+		Where("article_id = ?", articleID).
+		Order("commented_at ASC, id ASC").
+		Find(&comments).Error
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+	return comments, nil
+}
+
+// Targets 从 links 中挑选已保存评论数少于页面展示评论数的文章，从未抓取过的优先，其次差距大的优先。
+// 上次抓取后页面评论数没有变化的文章（如部分评论被折叠或删除，已保存数量永远追不上）
+// 在 retryAfter 内不再挑选，避免每次都选中同一批文章
+func (r *GormCommentRepository) Targets(ctx context.Context, links []string, limit int, retryAfter time.Duration) ([]CommentTarget, error) {
+	var targets []CommentTarget
+	if len(links) == 0 || limit <= 0 {
+		return targets, nil
+	}
+
+	stored := r.db.Model(&Comment{}).
+		Select("article_id, COUNT(*) AS total").
+		Group("article_id")
+	err := r.db.WithContext(ctx).Table("articles AS a").
+		Select("a.id AS article_id, a.link, COALESCE(c.total, 0) AS stored").
+		Joins("LEFT JOIN (?) AS c ON c.article_id = a.id", stored).
+		Where("a.link IN ? AND a.status = ? AND a.comments > COALESCE(c.total, 0)", links, ArticleStatusNormal).
+		Where("a.comments_checked_at IS NULL OR a.comments <> a.comments_checked_count OR a.comments_checked_at < ?",
+			time.Now().Add(-retryAfter)).
+		Order("a.comments_checked_at IS NOT NULL, a.comments - COALESCE(c.total, 0) DESC").
+		Limit(limit).
+		Scan(&targets).Error
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+	return targets, nil
+}
+
+// ReplyCounts 返回文章每条一级评论已保存的回复数
+func (r *GormCommentRepository) ReplyCounts(ctx context.Context, articleID int64) (map[string]int, error) {
+	var rows []struct {
+		RootCommentID string
+		Total         int
+	}
+	err := r.db.WithContext(ctx).Model(&Comment{}).
+		Select("root_comment_id, COUNT(*) AS total").
+		Where("article_id = ? AND root_comment_id <> ''", articleID).
+		Group("root_comment_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.RootCommentID] = row.Total
+	}
+	return counts, nil
+}
+
+// LatestRootCommentAt 返回文章已保存的最新一级评论的评论时间，没有评论时返回零值
+func (r *GormCommentRepository) LatestRootCommentAt(ctx context.Context, articleID int64) (time.Time, error) {
+	var latest sql.NullTime
+	err := r.db.WithContext(ctx).Model(&Comment{}).
+		Select("MAX(commented_at)").
+		Where("article_id = ? AND root_comment_id = ''", articleID).
+		Scan(&latest).Error
+	if err != nil {
+		return time.Time{}, wrapDBError(err)
+	}
+	return latest.Time, nil
+}
+
+// MarkChecked 记录文章本次抓取评论的时间和当前页面展示的评论数
+func (r *GormCommentRepository) MarkChecked(ctx context.Context, articleID int64) error {
+	err := r.db.WithContext(ctx).Model(&Article{}).Where("id = ?", articleID).Updates(map[string]interface{}{
+		"comments_checked_at":    time.Now(),
+		"comments_checked_count": gorm.Expr("comments"),
+	}).Error
+	return wrapDBError(err)
+}
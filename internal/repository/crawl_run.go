@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type CrawlRunRepository interface {
+	Create(ctx context.Context, run *CrawlRun) error
+	Save(ctx context.Context, run *CrawlRun) error
+	FindByID(ctx context.Context, id string) (*CrawlRun, error)
+	FindLatestSuccess(ctx context.Context, account, mode string) (*CrawlRun, error)
+}
+
+type GormCrawlRunRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCrawlRunRepository(db *gorm.DB) CrawlRunRepository {
+	return &GormCrawlRunRepository{db: db}
+}
+
+func (r *GormCrawlRunRepository) Create(ctx context.Context, run *CrawlRun) error {
+	return wrapDBError(r.db.WithContext(ctx).Create(run).Error)
+}
+
+// Save 更新任务记录的全部字段
+func (r *GormCrawlRunRepository) Save(ctx context.Context, run *CrawlRun) error {
+	return wrapDBError(r.db.WithContext(ctx).Save(run).Error)
+}
+
+// FindByID 根据任务ID查找，不存在时返回 gorm.ErrRecordNotFound
+func (r *GormCrawlRunRepository) FindByID(ctx context.Context, id string) (*CrawlRun, error) {
+	var run CrawlRun
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&run).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return &run, nil
+}
+
+// FindLatestSuccess 查找账号最近一次成功的指定模式任务，不存在时返回 gorm.ErrRecordNotFound
+func (r *GormCrawlRunRepository) FindLatestSuccess(ctx context.Context, account, mode string) (*CrawlRun, error) {
+	var run CrawlRun
+	err := r.db.WithContext(ctx).
+		Where("account = ? AND mode = ? AND status = ?", account, mode, CrawlRunSuccess).
+		Order("started_at DESC").
+		First(&run).Error
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+	return &run, nil
+}
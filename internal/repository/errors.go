@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"crawler/pkg/errcode"
+	"database/sql/driver"
+	"errors"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrDBUnavailable 数据库连接不可用
+var ErrDBUnavailable = errcode.New(errcode.DBUnavailable, "数据库不可用")
+
+// MySQL 连接类错误码：连接数过多、用户连接数超限、无法连接、连接断开
+var connectionErrorNumbers = map[uint16]bool{
+	1040: true,
+	1203: true,
+	2002: true,
+	2003: true,
+	2006: true,
+	2013: true,
+}
+
+// wrapDBError 将连接类错误归类为数据库不可用，其他错误原样返回
+func wrapDBError(err error) error {
+	if err == nil || !isConnectionError(err) {
+		return err
+	}
+	return errcode.Wrap(errcode.DBUnavailable, "数据库不可用", err)
+}
+
+func isConnectionError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return connectionErrorNumbers[mysqlErr.Number]
+	}
+	return false
+}
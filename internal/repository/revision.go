@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"crawler/internal/scraper"
+	"crawler/pkg/tracing"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"gorm.io/gorm"
+)
+
+type ArticleRevisionRepository interface {
+	SaveRevision(ctx context.Context, content *scraper.ArticleContent, runID string) (bool, error)
+	FindByArticle(ctx context.Context, articleID int64) ([]ArticleRevision, error)
+	FindByID(ctx context.Context, articleID, id int64) (*ArticleRevision, error)
+	StaleLinks(ctx context.Context, links []string, limit int) ([]string, error)
+	FindLatest(ctx context.Context, articleID int64) (*ArticleRevision, error)
+}
+
+type GormArticleRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormArticleRevisionRepository(db *gorm.DB) ArticleRevisionRepository {
+	return &GormArticleRevisionRepository{db: db}
+}
+
+// SaveRevision 保存文章正文。与最新版本哈希相同时只更新最近看到的任务和时间，
+// 否则新增一个版本，返回是否新增。文章不存在时返回 gorm.ErrRecordNotFound
+func (r *GormArticleRevisionRepository) SaveRevision(ctx context.Context, content *scraper.ArticleContent, runID string) (created bool, err error) {
+	ctx, span := tracing.Start(ctx, "db.save_revision",
+		semconv.DBSystemMySQL,
+		semconv.DBCollectionName("article_revisions"),
+	)
+	defer func() {
+		span.SetAttributes(attribute.Bool("crawler.revision_created", created))
+		tracing.End(span, err)
+	}()
+
+	hash := content.Hash()
+	now := time.Now()
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var article Article
+		if err := tx.Select("id").Where("link = ?", content.Link).First(&article).Error; err != nil {
+			return err
+		}
+
+		var latest ArticleRevision
+		err := tx.Select("id", "content_hash").
+			Where("article_id = ?", article.ID).
+			Order("id DESC").
+			First(&latest).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err == nil && latest.ContentHash == hash {
+			return tx.Model(&ArticleRevision{}).Where("id = ?", latest.ID).Updates(map[string]interface{}{
+				"last_run_id":  runID,
+				"last_seen_at": now,
+			}).Error
+		}
+
+		created = true
+		return tx.Create(&ArticleRevision{
+			ArticleID:   article.ID,
+			ContentHash: hash,
+			Title:       content.Title,
+			Content:     content.HTML,
+			Text:        content.Text,
+			CrawlRunID:  runID,
+			LastRunID:   runID,
+			LastSeenAt:  now,
+		}).Error
+	})
+	if err != nil {
+		return false, wrapDBError(err)
+	}
+	return created, nil
+}
+
+// FindByArticle 按时间顺序返回文章的全部版本，不含正文
+func (r *GormArticleRevisionRepository) FindByArticle(ctx context.Context, articleID int64) ([]ArticleRevision, error) {
+	var revisions []ArticleRevision
+	err := r.db.WithContext(ctx).
+		Omit("content", "text").
+		Where("article_id = ?", articleID).
+		Order("id ASC").
+		Find(&revisions).Error
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+	return revisions, nil
+}
+
+// FindByID 查找文章的指定版本，不存在时返回 gorm.ErrRecordNotFound
+func (r *GormArticleRevisionRepository) FindByID(ctx context.Context, articleID, id int64) (*ArticleRevision, error) {
+	var revision ArticleRevision
+	if err := r.db.WithContext(ctx).Where("article_id = ? AND id = ?", articleID, id).First(&revision).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return &revision, nil
+}
+
+// FindLatest 查找文章的最新版本，没有抓取过正文时返回 gorm.ErrRecordNotFound
+func (r *GormArticleRevisionRepository) FindLatest(ctx context.Context, articleID int64) (*ArticleRevision, error) {
+	var revision ArticleRevision
+	if err := r.db.WithContext(ctx).Where("article_id = ?", articleID).Order("id DESC").First(&revision).Error; err != nil {
+		return nil, wrapDBError(err)
+	}
+	return &revision, nil
+}
+
+// StaleLinks 从 links 中挑选最久没有抓取正文的文章，从未抓取过的优先
+func (r *GormArticleRevisionRepository) StaleLinks(ctx context.Context, links []string, limit int) ([]string, error) {
+	var result []string
+	if len(links) == 0 || limit <= 0 {
+		return result, nil
+	}
+
+	seen := r.db.Model(&ArticleRevision{}).
+		Select("article_id, MAX(last_seen_at) AS seen_at").
+		Group("article_id")
+	err := r.db.WithContext(ctx).Table("articles AS a").
+		Joins("LEFT JOIN (?) AS r ON r.article_id = a.id", seen).
+		Where("a.link IN ? AND a.status = ?", links, ArticleStatusNormal).
+		Order("r.seen_at IS NOT NULL, r.seen_at ASC").
+		Limit(limit).
+		Pluck("a.link", &result).Error
+	if err != nil {
+		return nil, wrapDBError(err)
+	}
+	return result, nil
+}
@@ -4,22 +4,194 @@ import "time"
 
 // Article GORM 文章模型
 type Article struct {
-	ID            int64     `gorm:"primaryKey;autoIncrement;comment:主键ID"`
-	Title         string    `gorm:"type:varchar(255);not null;comment:文章标题"`
-	Link          string    `gorm:"type:varchar(512);not null;uniqueIndex:uk_link;comment:文章链接"`
-	Description   string    `gorm:"type:text;comment:文章描述"`
-	PublishedTime string    `gorm:"type:varchar(64);comment:发布时间"`
-	ViewCount     int       `gorm:"type:int unsigned;default:0;comment:阅读数"`
-	Upvote        int       `gorm:"type:int unsigned;default:0;comment:点赞数"`
-	Comments      int       `gorm:"type:int unsigned;default:0;comment:评论数"`
-	Bookmarks     int       `gorm:"type:int unsigned;default:0;comment:收藏数"`
-	Likes         int       `gorm:"type:int unsigned;default:0;comment:喜欢数"`
-	Status        int8      `gorm:"type:tinyint(1);default:1;comment:状态:1-正常,0-删除"`
-	CreatedAt     time.Time `gorm:"autoCreateTime;comment:创建时间"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime;comment:更新时间"`
+	ID            int64      `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	Title         string     `gorm:"type:varchar(255);not null;comment:文章标题"`
+	Link          string     `gorm:"type:varchar(512);not null;uniqueIndex:uk_link;comment:文章链接"`
+	Description   string     `gorm:"type:text;comment:文章描述"`
+	PublishedTime string     `gorm:"type:varchar(64);comment:发布时间，页面展示的原始文本"`
+	PublishedAt   *time.Time `gorm:"type:datetime;index:idx_published_at;comment:发布时间，由原始文本解析"`
+	ViewCount     int        `gorm:"type:int unsigned;default:0;comment:阅读数"`
+	Upvote        int        `gorm:"type:int unsigned;default:0;comment:点赞数"`
+	Comments      int        `gorm:"type:int unsigned;default:0;comment:评论数"`
+	Bookmarks     int        `gorm:"type:int unsigned;default:0;comment:收藏数"`
+	Likes         int        `gorm:"type:int unsigned;default:0;comment:喜欢数"`
+	Status        int8       `gorm:"type:tinyint(1);default:1;index:idx_status;comment:状态:1-正常,0-删除"`
+	LastSeenAt    *time.Time `gorm:"comment:最近一次在爬取结果中出现的时间"`
+	DeletedAt     *time.Time `gorm:"comment:被标记为删除的时间"`
+	// 最近一次抓取评论的时间和当时页面展示的评论数，用于避免反复抓取评论数对不上的文章
+	CommentsCheckedAt    *time.Time `gorm:"comment:最近一次抓取评论的时间"`
+	CommentsCheckedCount int        `gorm:"type:int unsigned;default:0;comment:最近一次抓取评论时页面展示的评论数"`
+	CreatedAt            time.Time  `gorm:"autoCreateTime;comment:创建时间"`
+	UpdatedAt            time.Time  `gorm:"autoUpdateTime;comment:更新时间"`
 }
 
+// 文章状态
+const (
+	ArticleStatusDeleted int8 = 0
+	ArticleStatusNormal  int8 = 1
+)
+
 // TableName 指定表名
 func (Article) TableName() string {
 	return "articles"
 }
+
+// APIKey GORM API 密钥模型，仅保存密钥的哈希值
+type APIKey struct {
+	ID         int64      `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	Name       string     `gorm:"type:varchar(128);not null;comment:密钥名称"`
+	Prefix     string     `gorm:"type:varchar(16);not null;comment:密钥前缀，用于辨识"`
+	KeyHash    string     `gorm:"type:char(64);not null;uniqueIndex:uk_key_hash;comment:密钥SHA-256哈希"`
+	Scopes     string     `gorm:"type:varchar(512);not null;default:'';comment:权限范围，逗号分隔"`
+	ExpiresAt  *time.Time `gorm:"comment:过期时间，为空表示永不过期"`
+	LastUsedAt *time.Time `gorm:"comment:最后使用时间"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime;comment:创建时间"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// 爬取任务状态
+const (
+	CrawlRunRunning = "running"
+	CrawlRunSuccess = "success"
+	CrawlRunFailed  = "failed"
+)
+
+// 爬取任务模式
+const (
+	CrawlModeFull        = "full"
+	CrawlModeIncremental = "incremental"
+)
+
+// CrawlRun GORM 爬取任务记录模型
+type CrawlRun struct {
+	ID            string     `gorm:"type:char(36);primaryKey;comment:任务ID"`
+	Account       string     `gorm:"type:varchar(128);not null;index:idx_account_started;comment:爬取账号"`
+	Source        string     `gorm:"type:varchar(32);not null;comment:触发来源"`
+	Mode          string     `gorm:"type:varchar(16);not null;default:'full';comment:爬取模式:full/incremental"`
+	Status        string     `gorm:"type:varchar(16);not null;index:idx_status;comment:状态:running/success/failed"`
+	Error         string     `gorm:"type:text;comment:错误信息"`
+	FailReason    string     `gorm:"type:varchar(32);not null;default:'';comment:失败原因分类:blocked/not_logged_in等"`
+	Proxy         string     `gorm:"type:varchar(255);not null;default:'';comment:使用的代理，不含认证信息"`
+	ArticleCount  int        `gorm:"type:int unsigned;default:0;comment:提取文章数"`
+	Retries       int        `gorm:"type:int unsigned;default:0;comment:重试次数"`
+	DeletedCount  int        `gorm:"type:int unsigned;default:0;comment:本次标记为删除的文章数"`
+	RestoredCount int        `gorm:"type:int unsigned;default:0;comment:本次恢复的文章数"`
+	RevisionCount int        `gorm:"type:int unsigned;default:0;comment:本次新增的正文版本数"`
+	CommentCount  int        `gorm:"type:int unsigned;default:0;comment:本次保存的评论数"`
+	Logs          string     `gorm:"type:longtext;comment:任务日志，JSON Lines"`
+	StartedAt     time.Time  `gorm:"not null;index:idx_account_started;comment:开始时间"`
+	FinishedAt    *time.Time `gorm:"comment:结束时间"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime;comment:创建时间"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (CrawlRun) TableName() string {
+	return "crawl_runs"
+}
+
+// ArticleRevision GORM 文章正文版本模型，正文内容变化时新增一条
+type ArticleRevision struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	ArticleID   int64     `gorm:"not null;index:idx_article_created;comment:文章ID"`
+	ContentHash string    `gorm:"type:char(64);not null;index:idx_content_hash;comment:正文纯文本SHA-256哈希"`
+	Title       string    `gorm:"type:varchar(255);not null;comment:抓取时的文章标题"`
+	Content     string    `gorm:"type:longtext;comment:正文HTML"`
+	Text        string    `gorm:"type:longtext;comment:正文纯文本，用于比较差异"`
+	CrawlRunID  string    `gorm:"type:char(36);not null;index:idx_crawl_run;comment:首次发现该版本的任务ID"`
+	LastRunID   string    `gorm:"type:char(36);not null;comment:最近一次看到该版本的任务ID"`
+	LastSeenAt  time.Time `gorm:"not null;comment:最近一次看到该版本的时间"`
+	CreatedAt   time.Time `gorm:"autoCreateTime;index:idx_article_created;comment:创建时间"`
+}
+
+// TableName 指定表名
+func (ArticleRevision) TableName() string {
+	return "article_revisions"
+}
+
+// Comment GORM 文章评论模型，回复通过 root_comment_id 和 parent_comment_id 关联
+type Comment struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	ArticleID       int64     `gorm:"not null;index:idx_article_commented;comment:文章ID"`
+	CommentID       string    `gorm:"type:varchar(32);not null;uniqueIndex:uk_comment_id;comment:知乎评论ID"`
+	RootCommentID   string    `gorm:"type:varchar(32);not null;default:'';index:idx_root_comment;comment:所属一级评论ID，一级评论为空"`
+	ParentCommentID string    `gorm:"type:varchar(32);not null;default:'';comment:回复的评论ID，一级评论为空"`
+	AuthorName      string    `gorm:"type:varchar(128);not null;default:'';comment:评论者昵称"`
+	AuthorToken     string    `gorm:"type:varchar(128);not null;default:'';comment:评论者个人主页标识"`
+	Content         string    `gorm:"type:text;comment:评论内容HTML"`
+	Likes           int       `gorm:"type:int unsigned;default:0;comment:点赞数"`
+	ReplyCount      int       `gorm:"type:int unsigned;default:0;comment:回复数"`
+	CommentedAt     time.Time `gorm:"not null;index:idx_article_commented;comment:评论时间"`
+	CrawlRunID      string    `gorm:"type:char(36);not null;comment:最近一次更新该评论的任务ID"`
+	CreatedAt       time.Time `gorm:"autoCreateTime;comment:创建时间"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (Comment) TableName() string {
+	return "comments"
+}
+
+// AccountMetricsSnapshot GORM 账号数据快照模型，每次爬取保存一条
+type AccountMetricsSnapshot struct {
+	ID             int64     `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	Account        string    `gorm:"type:varchar(128);not null;index:idx_account_captured;comment:爬取账号"`
+	CrawlRunID     string    `gorm:"type:char(36);not null;comment:任务ID"`
+	Followers      int       `gorm:"type:int unsigned;default:0;comment:粉丝数"`
+	TotalReads     int       `gorm:"type:int unsigned;default:0;comment:总阅读数"`
+	TotalUpvotes   int       `gorm:"type:int unsigned;default:0;comment:总赞同数"`
+	DailyReads     int       `gorm:"type:int unsigned;default:0;comment:昨日阅读数"`
+	DailyUpvotes   int       `gorm:"type:int unsigned;default:0;comment:昨日赞同数"`
+	DailyFollowers int       `gorm:"type:int;default:0;comment:昨日新增粉丝数"`
+	DataDate       time.Time `gorm:"type:date;not null;comment:昨日数据对应的日期"`
+	CapturedAt     time.Time `gorm:"not null;index:idx_account_captured;comment:抓取时间"`
+	CreatedAt      time.Time `gorm:"autoCreateTime;comment:创建时间"`
+}
+
+// TableName 指定表名
+func (AccountMetricsSnapshot) TableName() string {
+	return "account_metrics_snapshots"
+}
+
+// AccountDailyMetric GORM 账号每日数据模型，来自创作中心的趋势数据，同一天多次爬取保留最后一次的数据
+type AccountDailyMetric struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	Account    string    `gorm:"type:varchar(128);not null;uniqueIndex:uk_account_date,priority:1;comment:爬取账号"`
+	Date       time.Time `gorm:"type:date;not null;uniqueIndex:uk_account_date,priority:2;comment:数据日期"`
+	Reads      int       `gorm:"column:view_count;type:int unsigned;default:0;comment:当天阅读数"` // reads 是 MySQL 保留字
+	Upvotes    int       `gorm:"type:int unsigned;default:0;comment:当天赞同数"`
+	Followers  int       `gorm:"type:int;default:0;comment:当天新增粉丝数"`
+	CrawlRunID string    `gorm:"type:char(36);not null;comment:最近一次更新数据的任务ID"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;comment:创建时间"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (AccountDailyMetric) TableName() string {
+	return "account_daily_metrics"
+}
+
+// ArticleStatSnapshot GORM 文章统计数据每日快照模型，同一天多次爬取保留最后一次的数据
+type ArticleStatSnapshot struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement;comment:主键ID"`
+	ArticleID    int64     `gorm:"not null;uniqueIndex:uk_article_date,priority:1;comment:文章ID"`
+	SnapshotDate time.Time `gorm:"type:date;not null;uniqueIndex:uk_article_date,priority:2;index:idx_snapshot_date;comment:快照日期"`
+	ViewCount    int       `gorm:"type:int unsigned;default:0;comment:阅读数"`
+	Upvote       int       `gorm:"type:int unsigned;default:0;comment:点赞数"`
+	Comments     int       `gorm:"type:int unsigned;default:0;comment:评论数"`
+	Bookmarks    int       `gorm:"type:int unsigned;default:0;comment:收藏数"`
+	Likes        int       `gorm:"type:int unsigned;default:0;comment:喜欢数"`
+	CrawlRunID   string    `gorm:"type:char(36);not null;comment:最近一次更新快照的任务ID"`
+	CreatedAt    time.Time `gorm:"autoCreateTime;comment:创建时间"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime;comment:更新时间"`
+}
+
+// TableName 指定表名
+func (ArticleStatSnapshot) TableName() string {
+	return "article_stat_snapshots"
+}
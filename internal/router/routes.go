@@ -1,6 +1,9 @@
 package router
 
 import (
+	"crawler/internal/middleware"
+	"crawler/internal/service"
+	"crawler/pkg/metrics"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -16,11 +19,70 @@ func (r *Router) setupHealthRoutes() {
 	})
 }
 
-// setupCrawlerRoutes 爬虫相关路由
+// setupMetricsRoutes 注册 Prometheus 指标路由
+func (r *Router) setupMetricsRoutes() {
+	r.engine.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
+
+// setupCrawlerRoutes 爬虫相关路由，每个路由声明所需的权限范围
 func (r *Router) setupCrawlerRoutes() {
 	api := r.engine.Group("/api")
+	api.Use(
+		middleware.IPRateLimit(r.config.RateLimit),
+		middleware.Auth(r.config, r.keyService),
+		middleware.RateLimit(r.config.RateLimit),
+	)
+
 	crawler := api.Group("/crawler")
 	{
-		crawler.POST("/zhihu", r.controller.HandleCrawl)
+		crawler.POST("/zhihu", middleware.RequireScope(service.ScopeCrawlTrigger), r.controllers.Crawler.HandleCrawl)
+	}
+
+	jobs := crawler.Group("/jobs", middleware.RequireScope(service.ScopeCrawlTrigger))
+	{
+		jobs.GET("/:id", r.controllers.Crawler.HandleGetJob)
+		jobs.GET("/:id/logs", r.controllers.Crawler.HandleJobLogs)
+		jobs.GET("/:id/events", r.controllers.Crawler.HandleJobEvents)
+		jobs.GET("/:id/artifacts", r.controllers.Crawler.HandleJobArtifacts)
+		jobs.GET("/:id/artifacts/:name", r.controllers.Crawler.HandleJobArtifact)
+	}
+
+	articles := api.Group("/articles", middleware.RequireScope(service.ScopeArticlesRead))
+	{
+		articles.GET("/:id/revisions", r.controllers.Article.HandleRevisions)
+		articles.GET("/:id/revisions/diff", r.controllers.Article.HandleRevisionDiff)
+		articles.GET("/:id/comments", r.controllers.Article.HandleComments)
+	}
+
+	accounts := api.Group("/accounts", middleware.RequireScope(service.ScopeArticlesRead))
+	{
+		accounts.GET("/:name/metrics", r.controllers.Account.HandleMetrics)
+		accounts.GET("/:name/daily", r.controllers.Account.HandleDaily)
+	}
+
+	analytics := api.Group("/analytics", middleware.RequireScope(service.ScopeArticlesRead))
+	{
+		analytics.GET("/top", r.controllers.Analytics.HandleTop)
+		analytics.GET("/daily", r.controllers.Analytics.HandleDaily)
+		analytics.GET("/engagement", r.controllers.Analytics.HandleEngagement)
+		analytics.GET("/cadence", r.controllers.Analytics.HandleCadence)
+	}
+
+	export := api.Group("/export", middleware.RequireScope(service.ScopeExportRun))
+	{
+		export.POST("/markdown", r.controllers.Export.HandleMarkdown)
+	}
+
+	keys := api.Group("/keys", middleware.RequireScope(service.ScopeAdmin))
+	{
+		keys.POST("", r.controllers.APIKey.HandleCreate)
+		keys.GET("", r.controllers.APIKey.HandleList)
+		keys.DELETE("/:id", r.controllers.APIKey.HandleDelete)
+	}
+
+	admin := api.Group("/admin", middleware.RequireScope(service.ScopeAdmin))
+	{
+		admin.GET("/log-level", r.controllers.Admin.HandleGetLogLevel)
+		admin.PUT("/log-level", r.controllers.Admin.HandleSetLogLevel)
 	}
 }
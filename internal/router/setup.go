@@ -4,8 +4,10 @@ import (
 	"context"
 	"crawler/internal/controller"
 	"crawler/internal/middleware"
+	"crawler/internal/service"
 	"crawler/pkg/config"
 	"crawler/pkg/logger"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -16,13 +18,25 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Controllers 路由依赖的控制器集合
+type Controllers struct {
+	Crawler   controller.ICrawlerController
+	APIKey    controller.IAPIKeyController
+	Admin     controller.IAdminController
+	Article   controller.IArticleController
+	Account   controller.IAccountController
+	Analytics controller.IAnalyticsController
+	Export    controller.IExportController
+}
+
 type Router struct {
-	config     *config.Config
-	engine     *gin.Engine
-	controller controller.ICrawlerController
+	config      *config.Config
+	engine      *gin.Engine
+	controllers Controllers
+	keyService  service.IAPIKeyService
 }
 
-func NewRouter(cfg *config.Config, controller controller.ICrawlerController) (*Router, error) {
+func NewRouter(cfg *config.Config, controllers Controllers, keyService service.IAPIKeyService) (*Router, error) {
 	gin.SetMode(cfg.Server.Mode)
 
 	ginEngine := gin.New()
@@ -32,15 +46,18 @@ func NewRouter(cfg *config.Config, controller controller.ICrawlerController) (*R
 	}
 
 	router := &Router{
-		config:     cfg,
-		engine:     ginEngine,
-		controller: controller,
+		config:      cfg,
+		engine:      ginEngine,
+		controllers: controllers,
+		keyService:  keyService,
 	}
 
 	// 注册业务路由
 	router.setupCrawlerRoutes()
 	// 注册健康检查路由
 	router.setupHealthRoutes()
+	// 注册监控指标路由
+	router.setupMetricsRoutes()
 
 	return router, nil
 }
@@ -53,7 +70,9 @@ func setupMiddleware(cfg *config.Config, engine *gin.Engine) error {
 	}
 
 	engine.Use(
+		middleware.Tracing(),
 		middleware.TraceID(),
+		middleware.Metrics(),
 		middleware.Cors(cfg),
 		gin.Logger(),
 		gin.Recovery(),
@@ -71,7 +90,10 @@ func (r *Router) ServeHTTP(addr string) error {
 		MaxHeaderBytes: r.config.Server.MaxHeaderBytes,
 	}
 
+	// 收到退出信号后 ListenAndServe 立即返回，需要等待 Shutdown 处理完进行中的请求
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		quit := make(chan os.Signal, 1)
 		signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 		<-quit
@@ -89,5 +111,10 @@ func (r *Router) ServeHTTP(addr string) error {
 	}()
 
 	logger.Info("HTTP服务启动", "addr", addr, "mode", gin.Mode())
-	return srv.ListenAndServe()
+	err := srv.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		<-done
+		return nil
+	}
+	return err
 }
@@ -0,0 +1,228 @@
+package scraper
+
+import (
+	"context"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/retry"
+	"crawler/pkg/tracing"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	playwright2 "github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// CreatorHomeURL 创作中心首页，展示账号的数据概览
+const CreatorHomeURL = "https://www.zhihu.com/creator"
+
+// 数据概览的卡片，每张卡片是 "标签\n数值" 两行文本
+var overviewSelectors = []string{"[class*='DataOverview']", "[class*='Overview']"}
+
+// overviewSelector 等待任意一个数据概览出现
+var overviewSelector = strings.Join(overviewSelectors, ", ")
+
+// overviewItemSelector 数据概览内的全部元素
+var overviewItemSelector = descendants(overviewSelectors)
+
+// creatorAPIPrefix 创作中心页面请求的数据接口，趋势图的每日数据从这些接口的响应中提取
+const creatorAPIPrefix = "https://www.zhihu.com/api/v4/creators/"
+
+// trendTimeout 数据概览出现后等待趋势数据加载的最长时间
+const trendTimeout = 10 * time.Second
+
+// AccountMetrics 账号整体数据，昨日数据为创作中心展示的前一天的数据
+type AccountMetrics struct {
+	Followers      int
+	TotalReads     int
+	TotalUpvotes   int
+	DailyReads     int
+	DailyUpvotes   int
+	DailyFollowers int
+	DataDate       time.Time // 昨日数据对应的日期
+	// Trend 趋势图中的每日数据，按日期升序，至少包含昨日数据（数据概览中有昨日数据时）
+	Trend []DailyMetrics
+}
+
+// DailyMetrics 某一天的账号数据
+type DailyMetrics struct {
+	Date      time.Time // Asia/Shanghai 时区零点
+	Reads     int
+	Upvotes   int
+	Followers int // 当天新增粉丝数，取关多时为负数
+}
+
+// ExtractAccountMetrics 打开创作中心首页并提取数据概览
+func ExtractAccountMetrics(ctx context.Context, page playwright2.Page, policy config.RetryConfig) (_ *AccountMetrics, err error) {
+	ctx, span := tracing.Start(ctx, "scraper.extract_account_metrics")
+	defer func() { tracing.End(span, err) }()
+	log := logger.FromContext(ctx).WithModule(logger.ModuleScraper)
+
+	// 趋势图由 canvas 绘制，每日数据只能从页面请求的接口响应中获取
+	var (
+		mu        sync.Mutex
+		responses []playwright2.Response
+	)
+	onResponse := func(resp playwright2.Response) {
+		if strings.HasPrefix(resp.URL(), creatorAPIPrefix) {
+			mu.Lock()
+			responses = append(responses, resp)
+			mu.Unlock()
+		}
+	}
+	page.OnResponse(onResponse)
+	defer page.RemoveListener("response", onResponse)
+
+	err = retry.Do(ctx, policy, retry.OpNavigate, func(int) error {
+		mu.Lock()
+		responses = nil
+		mu.Unlock()
+		if _, err := page.Goto(CreatorHomeURL); err != nil {
+			return WrapNavigationError(CreatorHomeURL, err)
+		}
+		// 跳转后立即检查是否被拦截，避免在验证页上等到超时
+		if err := DetectBlock(ctx, page); err != nil {
+			return err
+		}
+		if _, err := page.WaitForSelector(overviewSelector); err != nil {
+			if blockErr := DetectBlock(ctx, page); blockErr != nil {
+				return blockErr
+			}
+			return wrapWaitError(overviewSelector, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 取只有两行文本的元素作为候选的统计项，由 Go 侧按标签识别
+	result, err := page.EvalOnSelectorAll(overviewItemSelector, `items => items
+		.map(i => i.innerText || "")
+		.filter(t => t.length > 0 && t.length < 40 && t.trim().split(/\n+/).length === 2)`)
+	if err != nil {
+		return nil, fmt.Errorf("提取数据概览失败: %w", err)
+	}
+	texts, _ := result.([]interface{})
+
+	metrics := &AccountMetrics{DataDate: yesterday(time.Now())}
+	found, daily := 0, false
+	for _, item := range texts {
+		text, _ := item.(string)
+		label, value, ok := splitOverview(text)
+		if !ok {
+			continue
+		}
+		count, err := parseSignedCount(value)
+		if err != nil {
+			continue
+		}
+		if metrics.set(label, count) {
+			found++
+			daily = daily || strings.Contains(label, "昨日")
+		}
+	}
+	if found == 0 {
+		return nil, errcode.New(errcode.SelectorMissing, "创作中心首页没有找到数据概览")
+	}
+
+	// 趋势数据可能在数据概览之后才加载，等待超时不影响已提取的数据
+	_ = page.WaitForLoadState(playwright2.PageWaitForLoadStateOptions{
+		State:   playwright2.LoadStateNetworkidle,
+		Timeout: playwright2.Float(float64(trendTimeout.Milliseconds())),
+	})
+	mu.Lock()
+	captured := responses
+	mu.Unlock()
+	var bodies [][]byte
+	for _, resp := range captured {
+		if body, err := resp.Body(); err == nil {
+			bodies = append(bodies, body)
+		}
+	}
+	metrics.Trend = parseTrend(bodies)
+	if daily {
+		metrics.Trend = mergeDaily(metrics.Trend, DailyMetrics{
+			Date:      metrics.DataDate,
+			Reads:     metrics.DailyReads,
+			Upvotes:   metrics.DailyUpvotes,
+			Followers: metrics.DailyFollowers,
+		})
+	}
+
+	log.Debug("提取账号数据完成",
+		"followers", metrics.Followers,
+		"total_reads", metrics.TotalReads,
+		"total_upvotes", metrics.TotalUpvotes,
+		"trend_days", len(metrics.Trend),
+	)
+	span.SetAttributes(attribute.Int("scraper.followers", metrics.Followers))
+	return metrics, nil
+}
+
+// descendants 为每个选择器加上后代选择器，直接在选择器列表后拼接只会作用于最后一个
+func descendants(selectors []string) string {
+	parts := make([]string, len(selectors))
+	for i, selector := range selectors {
+		parts[i] = selector + " *"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// splitOverview 从 "标签\n数值" 或 "数值\n标签" 中分离标签和数值
+func splitOverview(text string) (label, value string, ok bool) {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	var parts []string
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			parts = append(parts, line)
+		}
+	}
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	if _, err := parseSignedCount(parts[1]); err == nil {
+		return parts[0], parts[1], true
+	}
+	if _, err := parseSignedCount(parts[0]); err == nil {
+		return parts[1], parts[0], true
+	}
+	return "", "", false
+}
+
+// set 按标签关键字设置对应的数据，识别成功时返回 true
+func (m *AccountMetrics) set(label string, count int) bool {
+	daily := strings.Contains(label, "昨日")
+	switch {
+	case strings.Contains(label, "阅读"):
+		if daily {
+			m.DailyReads = count
+		} else {
+			m.TotalReads = count
+		}
+	case strings.Contains(label, "赞同"):
+		if daily {
+			m.DailyUpvotes = count
+		} else {
+			m.TotalUpvotes = count
+		}
+	case strings.Contains(label, "粉丝"), strings.Contains(label, "关注者"):
+		if daily {
+			m.DailyFollowers = count
+		} else {
+			m.Followers = count
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// yesterday 返回 now 在 Asia/Shanghai 时区的前一天零点
+func yesterday(now time.Time) time.Time {
+	now = now.In(Shanghai)
+	return time.Date(now.Year(), now.Month(), now.Day()-1, 0, 0, 0, 0, Shanghai)
+}
@@ -1,13 +1,19 @@
 package scraper
 
 import (
+	"context"
+	"crawler/internal/progress"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
 	"crawler/pkg/logger"
-	"log"
-	"strconv"
+	"crawler/pkg/metrics"
+	"crawler/pkg/retry"
+	"crawler/pkg/tracing"
 	"strings"
 	"time"
 
 	playwright2 "github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type ArticleCard struct {
@@ -15,6 +21,7 @@ type ArticleCard struct {
 	Link          string
 	Description   string
 	PublishedTime string
+	PublishedAt   time.Time // 由 PublishedTime 解析，无法解析时为零值
 	Stats         ArticleStats
 }
 
@@ -26,117 +33,323 @@ type ArticleStats struct {
 	Likes     int
 }
 
-func ExtractData(page playwright2.Page) ([]ArticleCard, error) {
-	var articles []ArticleCard
+// Result 文章列表提取结果
+type Result struct {
+	Articles []ArticleCard
+	// Complete 滚动到了列表底部且没有卡片解析失败，即结果包含列表中的全部文章
+	Complete bool
+}
+
+// Options 文章列表提取参数
+type Options struct {
+	// Pacing 滚动节奏，未设置的项使用默认值
+	Pacing config.PacingConfig
+	// Retry 等待文章列表的重试策略，重试前会刷新页面
+	Retry config.RetryConfig
+	// StopAfterKnown 大于 0 时为增量模式，连续遇到这么多篇已保存的文章后停止滚动
+	StopAfterKnown int
+	// KnownLinks 返回 links 中已保存的文章链接，增量模式使用
+	KnownLinks func(ctx context.Context, links []string) (map[string]bool, error)
+	// OnFailure 提取出错时调用，用于保存页面现场。reason 标识出错的环节
+	OnFailure func(ctx context.Context, reason string)
+}
+
+// 出错环节
+const (
+	FailureListNotFound = "list_not_found"
+	FailureCardParse    = "card_parse_error"
+	FailureExtract      = "extract_failed"
+)
+
+func (o Options) failed(ctx context.Context, reason string) {
+	if o.OnFailure != nil {
+		o.OnFailure(ctx, reason)
+	}
+}
+
+func (o Options) incremental() bool {
+	return o.StopAfterKnown > 0 && o.KnownLinks != nil
+}
+
+// checkKnown 按页面顺序检查新文章是否已保存，返回更新后的连续计数和是否应停止。
+// 查询失败时不停止，继续按全量滚动
+func (o Options) checkKnown(ctx context.Context, articles []ArticleCard, consecutive int) (int, bool) {
+	links := make([]string, len(articles))
+	for i, article := range articles {
+		links[i] = article.Link
+	}
+	known, err := o.KnownLinks(ctx, links)
+	if err != nil {
+		logger.FromContext(ctx).WithModule(logger.ModuleScraper).Warn("查询已保存文章失败，继续滚动", "error", err)
+		return 0, false
+	}
+
+	for _, link := range links {
+		if known[link] {
+			consecutive++
+			if consecutive >= o.StopAfterKnown {
+				return consecutive, true
+			}
+		} else {
+			consecutive = 0
+		}
+	}
+	return consecutive, false
+}
+
+func ExtractData(ctx context.Context, page playwright2.Page, opts Options) (_ *Result, err error) {
+	ctx, span := tracing.Start(ctx, "scraper.extract")
+	defer func() { tracing.End(span, err) }()
+	log := logger.FromContext(ctx).WithModule(logger.ModuleScraper)
+
+	failure := FailureExtract
+	defer func() {
+		if err != nil {
+			opts.failed(ctx, failure)
+		}
+	}()
+
 	seenLinks := make(map[string]bool)
 	noNewDataCount := 0 // 记录连续没有新数据的次数
+	knownCount := 0     // 增量模式下连续遇到已保存文章的次数
+	pacer := newPacer(page, opts.Pacing)
+	cardErrorReported := false
+	statsMissingReported := false
+	reachedBottom := false
+	var articles []ArticleCard
 
 	// 等待列表容器加载
-	_, err := page.WaitForSelector("div[role='list']")
-	if err != nil {
+	if err := waitForList(ctx, page, opts.Retry); err != nil {
+		failure = FailureListNotFound
+		if code := errcode.Of(err); code == errcode.Blocked || code == errcode.NotLoggedIn {
+			failure = code.String()
+		}
 		return nil, err
 	}
 
-	logger.Info("开始提取文章数据")
+	log.Info("开始提取文章数据")
 
 	// 无限循环，直到确认没有新数据
-	for {
-		// 执行滚动
-		_, err := page.Evaluate(`window.scrollTo(0, document.body.scrollHeight)`)
+	for iteration := 1; ; iteration++ {
+		previousCount := len(articles)
+		var cardErrors, statsMissing int
+		articles, cardErrors, statsMissing, err = scrollAndCollect(ctx, page, pacer, iteration, articles, seenLinks)
 		if err != nil {
 			return nil, err
 		}
-
-		// 等待新内容加载（3秒）
-		time.Sleep(3 * time.Second)
-
-		// 获取当前所有文章卡片
-		cards, err := page.QuerySelectorAll(".CreationManage-CreationCard")
-		if err != nil {
-			return nil, err
+		// 没有识别到统计项时统计数据全部为 0，多半是页面结构变了，只提示一次
+		if statsMissing > 0 && !statsMissingReported {
+			statsMissingReported = true
+			log.Warn("文章卡片中没有识别到统计数据，统计数据记为 0，请检查 .css-150duks 的页面结构",
+				"cards", statsMissing,
+				"iteration", iteration,
+			)
 		}
-
-		previousCount := len(articles)
-
-		// 处理新的文章卡片
-		for _, card := range cards {
-			article, err := extractCardDetails(card)
-			if err != nil {
-				logger.Error("提取文章详情失败", "error", err)
-				continue
-			}
-
-			// 检查是否已经处理过这篇文章
-			if !seenLinks[article.Link] {
-				seenLinks[article.Link] = true
-				articles = append(articles, article)
-				logger.Info("成功提取文章",
-					"title", article.Title,
-					"link", article.Link,
-					"total_articles", len(articles),
-				)
-			}
+		// 卡片解析失败不中断提取，只在第一次出现时保存现场
+		if cardErrors > 0 && !cardErrorReported {
+			cardErrorReported = true
+			opts.failed(ctx, FailureCardParse)
 		}
 
 		// 检查是否有新数据
 		if len(articles) == previousCount {
 			noNewDataCount++
-			logger.Info("本次滚动未发现新文章",
+			log.Info("本次滚动未发现新文章",
 				"retry_count", noNewDataCount,
 				"total_articles", len(articles),
 			)
-			// 连续3次没有新数据，认为已经到底
-			if noNewDataCount >= 3 {
-				logger.Info("已到达页面底部，停止提取",
+			// 连续多轮没有新数据，认为已经到底
+			if noNewDataCount >= pacer.config.MaxEmptyRounds {
+				log.Info("已到达页面底部，停止提取",
 					"total_articles", len(articles),
 				)
+				reachedBottom = true
 				break
 			}
 		} else {
 			// 有新数据，重置计数器
 			noNewDataCount = 0
+
+			if opts.incremental() {
+				var stop bool
+				knownCount, stop = opts.checkKnown(ctx, articles[previousCount:], knownCount)
+				if stop {
+					log.Info("连续遇到已保存的文章，增量爬取结束",
+						"known", knownCount,
+						"total_articles", len(articles),
+					)
+					break
+				}
+			}
+		}
+
+		if err := pacer.pause(ctx); err != nil {
+			return nil, err
 		}
 	}
 
-	return articles, nil
+	complete := reachedBottom && !cardErrorReported
+	span.SetAttributes(
+		attribute.Int("scraper.articles", len(articles)),
+		attribute.Bool("scraper.complete", complete),
+	)
+	return &Result{Articles: articles, Complete: complete}, nil
 }
 
-func extractCardDetails(card playwright2.ElementHandle) (ArticleCard, error) {
+// waitForList 等待文章列表出现，超时后刷新页面重试。被拦截或登录失效时不重试
+func waitForList(ctx context.Context, page playwright2.Page, policy config.RetryConfig) error {
+	const selector = "div[role='list']"
+	return retry.Do(ctx, policy, retry.OpWaitSelector, func(attempt int) error {
+		if attempt > 1 {
+			if _, err := page.Reload(); err != nil {
+				return WrapNavigationError(page.URL(), err)
+			}
+			if err := DetectBlock(ctx, page); err != nil {
+				return err
+			}
+		}
+		if _, err := page.WaitForSelector(selector); err != nil {
+			// 列表没有出现时优先判断是否被拦截或登录失效
+			if blockErr := DetectBlock(ctx, page); blockErr != nil {
+				return blockErr
+			}
+			return wrapWaitError(selector, err)
+		}
+		return nil
+	})
+}
+
+// scrollAndCollect 执行一次滚动并收集新出现的文章卡片，返回更新后的文章列表和解析失败的卡片数
+// scrollAndCollect 滚动一轮并解析新出现的卡片，返回解析失败的卡片数和没有识别到任何统计项的卡片数
+func scrollAndCollect(ctx context.Context, page playwright2.Page, pacer *pacer, iteration int, articles []ArticleCard, seenLinks map[string]bool) (_ []ArticleCard, cardErrors, statsMissing int, err error) {
+	ctx, span := tracing.Start(ctx, "scraper.scroll", attribute.Int("scraper.iteration", iteration))
+	defer func() { tracing.End(span, err) }()
+	log := logger.FromContext(ctx).WithModule(logger.ModuleScraper)
+
+	previousCards, err := page.Locator(cardSelector).Count()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 分步滚动到底部
+	steps, err := pacer.scroll(ctx)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	metrics.ScrollIterationsTotal.Inc()
+
+	// 等待新卡片出现或网络空闲
+	settled, err := pacer.settle(ctx, previousCards)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	// 获取当前所有文章卡片
+	cards, err := page.QuerySelectorAll(cardSelector)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	previousCount := len(articles)
+	log.Debug("滚动完成，开始解析文章卡片",
+		"iteration", iteration,
+		"wheel_steps", steps,
+		"settled", settled,
+		"cards", len(cards),
+	)
+
+	// 处理新的文章卡片
+	for _, card := range cards {
+		article, statItems, err := extractCardDetails(ctx, card)
+		if err != nil {
+			cardErrors++
+			metrics.ExtractionErrorsTotal.Inc()
+			log.Error("提取文章详情失败", "error", err)
+			continue
+		}
+		if statItems == 0 {
+			statsMissing++
+		}
+
+		// 检查是否已经处理过这篇文章
+		if !seenLinks[article.Link] {
+			seenLinks[article.Link] = true
+			articles = append(articles, article)
+			log.Info("成功提取文章",
+				"title", article.Title,
+				"link", article.Link,
+				"total_articles", len(articles),
+			)
+			progress.Report(ctx, progress.EventArticleExtracted, map[string]interface{}{
+				"title": article.Title,
+				"link":  article.Link,
+				"total": len(articles),
+			})
+			log.Debug("文章统计数据",
+				"link", article.Link,
+				"published_time", article.PublishedTime,
+				"stats", article.Stats,
+			)
+		}
+	}
+
+	progress.Report(ctx, progress.EventScrolled, map[string]interface{}{
+		"iteration": iteration,
+		"new":       len(articles) - previousCount,
+		"total":     len(articles),
+	})
+	span.SetAttributes(
+		attribute.Int("scraper.cards", len(cards)),
+		attribute.Int("scraper.wheel_steps", steps),
+		attribute.String("scraper.settled", settled),
+		attribute.Int("scraper.new_articles", len(articles)-previousCount),
+		attribute.Int("scraper.card_errors", cardErrors),
+	)
+	return articles, cardErrors, statsMissing, nil
+}
+
+// extractCardDetails 解析文章卡片，同时返回识别到的统计项数量
+func extractCardDetails(ctx context.Context, card playwright2.ElementHandle) (ArticleCard, int, error) {
 	var article ArticleCard
 	var err error
 
 	article.Title, err = getText(card, ".CreationCardTitle-wrapper")
 	if err != nil {
-		return article, err
+		return article, 0, err
 	}
 
 	linkElement, err := card.QuerySelector("a.css-959ia8")
 	if err != nil {
-		return article, err
+		return article, 0, err
 	}
 	article.Link, err = linkElement.GetAttribute("href")
 	if err != nil {
-		return article, err
+		return article, 0, err
 	}
 
 	article.Description, err = getText(card, ".CreationCardContent-text span")
 	if err != nil {
-		return article, err
+		return article, 0, err
 	}
 
 	article.PublishedTime, err = getText(card, ".css-zzavo4")
 	if err != nil {
-		return article, err
+		return article, 0, err
+	}
+	if publishedAt, err := ParsePublishedTime(article.PublishedTime, time.Now()); err == nil {
+		article.PublishedAt = publishedAt
+	} else {
+		logger.FromContext(ctx).WithModule(logger.ModuleScraper).Warn("解析发布时间失败", "published_time", article.PublishedTime, "error", err)
 	}
 
 	// Extract statistics
-	stats, err := extractStats(card)
+	stats, statItems, err := extractStats(ctx, card)
 	if err != nil {
-		return article, err
+		return article, 0, err
 	}
 	article.Stats = stats
 
-	return article, nil
+	return article, statItems, nil
 }
 
 func getText(card playwright2.ElementHandle, selector string) (string, error) {
@@ -147,41 +360,70 @@ func getText(card playwright2.ElementHandle, selector string) (string, error) {
 	return element.InnerText()
 }
 
-func extractStats(card playwright2.ElementHandle) (ArticleStats, error) {
-	var stats ArticleStats
-	statElements, err := card.QuerySelectorAll(".css-150duks div")
+// statLabels 统计项标签
+var statLabels = []string{"阅读", "赞同", "评论", "收藏", "喜欢"}
+
+// extractStats 按 DOM 结构解析统计数据：每个统计项是 .css-150duks 的一个子元素，
+// 其中同时包含数字和标签，数字和标签在同一个子元素内配对。返回识别到的统计项数量
+func extractStats(ctx context.Context, card playwright2.ElementHandle) (ArticleStats, int, error) {
+	items, err := card.EvalOnSelectorAll(".css-150duks > div", `items => items.map(item => item.innerText)`)
 	if err != nil {
-		return stats, err
+		return ArticleStats{}, 0, err
 	}
+	values, _ := items.([]interface{})
+	texts := make([]string, 0, len(values))
+	for _, item := range values {
+		text, _ := item.(string)
+		texts = append(texts, text)
+	}
+	stats, matched := parseStats(ctx, texts)
+	return stats, matched, nil
+}
 
-	var lastNumber int
-	for _, element := range statElements {
-		textContent, err := element.InnerText()
+// parseStats 从每个统计项子元素的文本中解析统计数据，数字和标签不在同一个子元素内的不会被识别。
+// 返回识别到的统计项数量
+func parseStats(ctx context.Context, texts []string) (ArticleStats, int) {
+	var stats ArticleStats
+	matched := 0
+	for _, text := range texts {
+		label, value, ok := splitStat(text)
+		if !ok {
+			continue
+		}
+		count, err := ParseCount(value)
 		if err != nil {
-			log.Printf("无法获取统计信息文本: %v", err)
+			logger.FromContext(ctx).WithModule(logger.ModuleScraper).Warn("无法解析统计数量", "label", label, "text", text, "error", err)
 			continue
 		}
-		textContent = strings.TrimSpace(textContent) // 清除两侧可能的空格
+		stats.set(label, count)
+		matched++
+	}
+	return stats, matched
+}
 
-		if number, err := strconv.Atoi(textContent); err == nil {
-			// 如果转换成功，说明是数字，记录下来
-			lastNumber = number
-		} else {
-			// 如果转换失败，说明是文本标签，与上一个数字配对
-			switch textContent {
-			case "阅读":
-				stats.Reads = lastNumber
-			case "赞同":
-				stats.Upvote = lastNumber
-			case "评论":
-				stats.Comments = lastNumber
-			case "收藏":
-				stats.Bookmarks = lastNumber
-			case "喜欢":
-				stats.Likes = lastNumber
-			}
-			lastNumber = 0 // 重置数字，避免错误关联
+// splitStat 从统计项文本中分离标签和数量，如 "1.2 万\n阅读" -> ("阅读", "1.2 万")
+func splitStat(text string) (label, value string, ok bool) {
+	text = strings.TrimSpace(text)
+	for _, label := range statLabels {
+		if strings.Contains(text, label) {
+			value = strings.TrimSpace(strings.Replace(text, label, "", 1))
+			return label, value, value != ""
 		}
 	}
-	return stats, nil
+	return "", "", false
+}
+
+func (s *ArticleStats) set(label string, count int) {
+	switch label {
+	case "阅读":
+		s.Reads = count
+	case "赞同":
+		s.Upvote = count
+	case "评论":
+		s.Comments = count
+	case "收藏":
+		s.Bookmarks = count
+	case "喜欢":
+		s.Likes = count
+	}
 }
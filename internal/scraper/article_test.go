@@ -0,0 +1,89 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSplitStat(t *testing.T) {
+	tests := []struct {
+		text  string
+		label string
+		value string
+		ok    bool
+	}{
+		{"1.2 万\n阅读", "阅读", "1.2 万", true},
+		{"阅读 1,024", "阅读", "1,024", true},
+		{"  36\n赞同  ", "赞同", "36", true},
+		{"3k评论", "评论", "3k", true},
+		{"收藏\n0", "收藏", "0", true},
+		{"喜欢", "喜欢", "", false},
+		{"", "", "", false},
+		{"12\n分享", "", "", false},
+	}
+	for _, tt := range tests {
+		label, value, ok := splitStat(tt.text)
+		if label != tt.label || value != tt.value || ok != tt.ok {
+			t.Errorf("splitStat(%q) = (%q, %q, %v)，期望 (%q, %q, %v)",
+				tt.text, label, value, ok, tt.label, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestArticleStatsSet(t *testing.T) {
+	var stats ArticleStats
+	for _, text := range []string{"1.2 万\n阅读", "36\n赞同", "5\n评论", "1k\n收藏", "7\n喜欢", "12\n分享", "abc\n阅读"} {
+		label, value, ok := splitStat(text)
+		if !ok {
+			continue
+		}
+		count, err := ParseCount(value)
+		if err != nil {
+			continue
+		}
+		stats.set(label, count)
+	}
+
+	want := ArticleStats{Reads: 12000, Upvote: 36, Comments: 5, Bookmarks: 1000, Likes: 7}
+	if stats != want {
+		t.Errorf("统计数据 = %+v，期望 %+v", stats, want)
+	}
+}
+
+func TestParseStats(t *testing.T) {
+	tests := []struct {
+		name    string
+		texts   []string
+		want    ArticleStats
+		matched int
+	}{
+		{
+			"每个子元素包含数字和标签",
+			[]string{"1.2 万\n阅读", "36\n赞同", "5\n评论", "1k\n收藏", "7\n喜欢"},
+			ArticleStats{Reads: 12000, Upvote: 36, Comments: 5, Bookmarks: 1000, Likes: 7},
+			5,
+		},
+		{
+			"跳过无法识别和无法解析的子元素",
+			[]string{"36\n赞同", "12\n分享", "abc\n阅读", ""},
+			ArticleStats{Upvote: 36},
+			1,
+		},
+		{
+			// 数字和标签分别在不同的子元素中时无法配对
+			"数字和标签不在同一个子元素",
+			[]string{"1.2 万", "阅读", "36", "赞同"},
+			ArticleStats{},
+			0,
+		},
+		{"没有统计项", nil, ArticleStats{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats, matched := parseStats(context.Background(), tt.texts)
+			if stats != tt.want || matched != tt.matched {
+				t.Errorf("parseStats = (%+v, %d)，期望 (%+v, %d)", stats, matched, tt.want, tt.matched)
+			}
+		})
+	}
+}
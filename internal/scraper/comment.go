@@ -0,0 +1,256 @@
+package scraper
+
+import (
+	"context"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/retry"
+	"crawler/pkg/tracing"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	playwright2 "github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	rootCommentURL  = "https://www.zhihu.com/api/v4/comment_v5/articles/%s/root_comment?order_by=ts&limit=20"
+	childCommentURL = "https://www.zhihu.com/api/v4/comment_v5/comment/%s/child_comment?order_by=ts&limit=20"
+)
+
+var articleIDPattern = regexp.MustCompile(`/p/(\d+)`)
+
+// Comment 文章评论，回复通过 RootID 和 ParentID 关联
+type Comment struct {
+	ID          string
+	RootID      string // 所属的一级评论，一级评论为空
+	ParentID    string // 回复的评论，一级评论为空
+	AuthorName  string
+	AuthorToken string
+	Content     string // 评论 HTML
+	Likes       int
+	ReplyCount  int
+	CreatedAt   time.Time
+}
+
+// CommentOptions 评论抓取参数
+type CommentOptions struct {
+	// KnownReplies 已保存的每条一级评论的回复数，不为空时为增量抓取：
+	// 只有新评论和回复数多于已保存回复数的评论才抓取回复
+	KnownReplies map[string]int
+	// Since 已保存的最新一级评论的时间，不为零时一级评论翻到不晚于这个时间的页面后停止翻页
+	Since time.Time
+	// Pacing 翻页之间的停顿
+	Pacing config.PacingConfig
+	// Retry 请求评论接口的重试策略
+	Retry config.RetryConfig
+}
+
+// ArticleID 从文章链接中解析知乎文章ID
+func ArticleID(link string) (string, bool) {
+	m := articleIDPattern.FindStringSubmatch(link)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+// FetchComments 通过评论接口抓取文章的一级评论和回复，请求复用浏览器上下文的 cookies。
+// 增量抓取时跳过回复数没有变化的评论的回复；一级评论按时间倒序返回，翻到已保存的评论后停止翻页，
+// 更早的评论的点赞数、回复数和新回复留给全量抓取更新
+func FetchComments(ctx context.Context, request playwright2.APIRequestContext, link string, opts CommentOptions) (_ []Comment, err error) {
+	id, ok := ArticleID(link)
+	if !ok {
+		return nil, fmt.Errorf("无法从链接解析文章ID: %s", link)
+	}
+	ctx, span := tracing.Start(ctx, "scraper.fetch_comments", attribute.String("zhihu.article_id", id))
+	defer func() { tracing.End(span, err) }()
+	log := logger.FromContext(ctx).WithModule(logger.ModuleScraper)
+
+	f := &commentFetcher{request: request, opts: opts}
+	var comments []Comment
+	next := fmt.Sprintf(rootCommentURL, id)
+	for next != "" {
+		page, err := f.fetch(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		next = page.next()
+		if page.reached(opts.Since) {
+			next = ""
+		}
+
+		for _, item := range page.Data {
+			root := item.comment("", "")
+			comments = append(comments, root)
+			if known, ok := opts.KnownReplies[root.ID]; ok && known >= item.ChildCommentCount {
+				continue
+			}
+
+			replies, err := f.replies(ctx, item)
+			if err != nil {
+				return nil, err
+			}
+			comments = append(comments, replies...)
+		}
+	}
+
+	log.Debug("抓取评论完成",
+		"link", link,
+		"count", len(comments),
+	)
+	span.SetAttributes(attribute.Int("scraper.comments", len(comments)))
+	return comments, nil
+}
+
+type commentFetcher struct {
+	request  playwright2.APIRequestContext
+	opts     CommentOptions
+	requests int
+}
+
+// replies 返回一级评论的全部回复，接口内联的回复不完整时翻页抓取
+func (f *commentFetcher) replies(ctx context.Context, root commentItem) ([]Comment, error) {
+	rootID := string(root.ID)
+	items := root.ChildComments
+	if root.ChildCommentCount > len(items) {
+		items = nil
+		next := fmt.Sprintf(childCommentURL, rootID)
+		for next != "" {
+			page, err := f.fetch(ctx, next)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, page.Data...)
+			next = page.next()
+		}
+	}
+
+	replies := make([]Comment, 0, len(items))
+	for _, item := range items {
+		replies = append(replies, item.comment(rootID, rootID))
+	}
+	return replies, nil
+}
+
+// fetch 请求一页评论，两次请求之间随机停顿
+func (f *commentFetcher) fetch(ctx context.Context, url string) (*commentPage, error) {
+	if f.requests > 0 {
+		if err := Pause(ctx, f.opts.Pacing); err != nil {
+			return nil, err
+		}
+	}
+	f.requests++
+
+	var page commentPage
+	err := retry.Do(ctx, f.opts.Retry, retry.OpFetchComments, func(int) error {
+		resp, err := f.request.Get(url)
+		if err != nil {
+			return WrapNavigationError(url, err)
+		}
+		defer resp.Dispose()
+
+		switch status := resp.Status(); {
+		case status == http.StatusUnauthorized:
+			return ErrLoggedOut
+		case status == http.StatusForbidden:
+			return ErrBlocked
+		case status == http.StatusTooManyRequests || status >= 500:
+			return errcode.New(errcode.NetworkError, fmt.Sprintf("评论接口返回 %d", status))
+		case !resp.Ok():
+			return fmt.Errorf("评论接口返回 %d: %s", status, url)
+		}
+		if err := resp.JSON(&page); err != nil {
+			return fmt.Errorf("解析评论接口响应失败: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// commentPage 评论接口的一页数据
+type commentPage struct {
+	Data   []commentItem `json:"data"`
+	Paging struct {
+		IsEnd bool   `json:"is_end"`
+		Next  string `json:"next"`
+	} `json:"paging"`
+}
+
+func (p *commentPage) next() string {
+	if p.Paging.IsEnd || len(p.Data) == 0 {
+		return ""
+	}
+	return p.Paging.Next
+}
+
+// reached 判断这一页是否已经包含不晚于 since 的评论，since 为零值时返回 false
+func (p *commentPage) reached(since time.Time) bool {
+	if since.IsZero() {
+		return false
+	}
+	for _, item := range p.Data {
+		if item.CreatedTime <= since.Unix() {
+			return true
+		}
+	}
+	return false
+}
+
+type commentItem struct {
+	ID                flexibleID    `json:"id"`
+	Content           string        `json:"content"`
+	CreatedTime       int64         `json:"created_time"`
+	LikeCount         int           `json:"like_count"`
+	ChildCommentCount int           `json:"child_comment_count"`
+	ReplyCommentID    flexibleID    `json:"reply_comment_id"`
+	ChildComments     []commentItem `json:"child_comments"`
+	Author            struct {
+		Name     string `json:"name"`
+		URLToken string `json:"url_token"`
+	} `json:"author"`
+}
+
+// comment 转换为评论，回复没有指定回复对象时视为回复一级评论
+func (i commentItem) comment(rootID, defaultParent string) Comment {
+	parentID := string(i.ReplyCommentID)
+	if parentID == "" || parentID == "0" {
+		parentID = defaultParent
+	}
+	return Comment{
+		ID:          string(i.ID),
+		RootID:      rootID,
+		ParentID:    parentID,
+		AuthorName:  i.Author.Name,
+		AuthorToken: i.Author.URLToken,
+		Content:     i.Content,
+		Likes:       i.LikeCount,
+		ReplyCount:  i.ChildCommentCount,
+		CreatedAt:   time.Unix(i.CreatedTime, 0).In(Shanghai),
+	}
+}
+
+// flexibleID 评论ID，接口中可能是数字也可能是字符串
+type flexibleID string
+
+func (id *flexibleID) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*id = flexibleID(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*id = flexibleID(strings.TrimSpace(n.String()))
+	return nil
+}
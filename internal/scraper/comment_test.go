@@ -0,0 +1,35 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommentPageReached(t *testing.T) {
+	since := time.Date(2025, 3, 10, 12, 0, 0, 0, Shanghai)
+	page := func(times ...time.Time) *commentPage {
+		p := &commentPage{}
+		for _, at := range times {
+			p.Data = append(p.Data, commentItem{CreatedTime: at.Unix()})
+		}
+		return p
+	}
+
+	tests := []struct {
+		name  string
+		page  *commentPage
+		since time.Time
+		want  bool
+	}{
+		{"全部晚于已保存评论", page(since.Add(2*time.Hour), since.Add(time.Minute)), since, false},
+		{"包含已保存的最新评论", page(since.Add(time.Hour), since), since, true},
+		{"包含更早的评论", page(since.Add(time.Hour), since.Add(-time.Hour)), since, true},
+		{"没有已保存评论", page(since.Add(-time.Hour)), time.Time{}, false},
+		{"空页", page(), since, false},
+	}
+	for _, tt := range tests {
+		if got := tt.page.reached(tt.since); got != tt.want {
+			t.Errorf("%s: reached = %v，期望 %v", tt.name, got, tt.want)
+		}
+	}
+}
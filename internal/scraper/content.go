@@ -0,0 +1,123 @@
+package scraper
+
+import (
+	"context"
+	"crawler/pkg/config"
+	"crawler/pkg/logger"
+	"crawler/pkg/retry"
+	"crawler/pkg/tracing"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	playwright2 "github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// 文章正文所在的元素，按顺序逐个尝试，使用第一个存在的
+var contentSelectors = []string{".Post-RichTextContainer .RichText", ".Post-RichText", ".RichText.ztext"}
+
+// contentSelector 等待任意一个正文元素出现
+var contentSelector = strings.Join(contentSelectors, ", ")
+
+// ArticleContent 文章正文
+type ArticleContent struct {
+	Link  string
+	Title string
+	HTML  string // 正文 HTML，导出时使用
+	Text  string // 正文纯文本，用于计算版本和比较差异
+}
+
+// Hash 正文纯文本的 SHA-256，相同内容的正文哈希相同
+func (c *ArticleContent) Hash() string {
+	sum := sha256.Sum256([]byte(c.Text))
+	return hex.EncodeToString(sum[:])
+}
+
+// ArticleURL 将创作中心列表中的链接转换为可访问的文章地址
+func ArticleURL(link string) string {
+	switch {
+	case strings.HasPrefix(link, "//"):
+		return "https:" + link
+	case strings.HasPrefix(link, "/"):
+		return "https://zhuanlan.zhihu.com" + link
+	case strings.HasPrefix(link, "http://"):
+		return "https://" + strings.TrimPrefix(link, "http://")
+	}
+	return link
+}
+
+// ExtractContent 打开文章页并提取正文，正文没有出现时先判断是否被拦截或登录失效
+func ExtractContent(ctx context.Context, page playwright2.Page, link string, policy config.RetryConfig) (_ *ArticleContent, err error) {
+	articleURL := ArticleURL(link)
+	ctx, span := tracing.Start(ctx, "scraper.extract_content", attribute.String("url.full", articleURL))
+	defer func() { tracing.End(span, err) }()
+	log := logger.FromContext(ctx).WithModule(logger.ModuleScraper)
+
+	err = retry.Do(ctx, policy, retry.OpNavigate, func(int) error {
+		if _, err := page.Goto(articleURL); err != nil {
+			return WrapNavigationError(articleURL, err)
+		}
+		// 跳转后立即检查是否被拦截，避免在验证页上等到超时
+		if err := DetectBlock(ctx, page); err != nil {
+			return err
+		}
+		if _, err := page.WaitForSelector(contentSelector); err != nil {
+			if blockErr := DetectBlock(ctx, page); blockErr != nil {
+				return blockErr
+			}
+			return wrapWaitError(contentSelector, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := page.Evaluate(`(selectors) => {
+		const body = selectors.map(s => document.querySelector(s)).find(el => el);
+		const title = document.querySelector(".Post-Title, h1");
+		return {
+			title: title ? title.innerText.trim() : document.title,
+			html: body.innerHTML,
+			text: body.innerText,
+		};
+	}`, contentSelectors)
+	if err != nil {
+		return nil, fmt.Errorf("提取正文失败: %w", err)
+	}
+	fields, _ := result.(map[string]interface{})
+	content := &ArticleContent{Link: link}
+	content.Title, _ = fields["title"].(string)
+	content.HTML, _ = fields["html"].(string)
+	text, _ := fields["text"].(string)
+	content.Text = normalizeText(text)
+
+	log.Debug("提取正文完成",
+		"link", link,
+		"length", len(content.Text),
+	)
+	span.SetAttributes(attribute.Int("scraper.content_length", len(content.Text)))
+	return content, nil
+}
+
+// normalizeText 去掉行尾空白和多余空行，避免排版差异产生新版本
+func normalizeText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	result := make([]string, 0, len(lines))
+	blank := false
+	for _, line := range lines {
+		line = strings.TrimRight(line, " \t ")
+		if line == "" {
+			if blank {
+				continue
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		result = append(result, line)
+	}
+	return strings.TrimSpace(strings.Join(result, "\n"))
+}
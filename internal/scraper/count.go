@@ -0,0 +1,86 @@
+package scraper
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// signPrefixes 数量前的正负号，负号包括全角和 Unicode 减号
+var signPrefixes = []struct {
+	prefix string
+	sign   int
+}{
+	{"+", 1},
+	{"＋", 1},
+	{"-", -1},
+	{"－", -1},
+	{"−", -1},
+}
+
+// countNumber 去掉单位后只允许普通的十进制数，排除 ParseFloat 接受的 1e5、0x1p3、Inf、NaN 等写法
+var countNumber = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`)
+
+// countUnits 数量单位对应的倍数
+var countUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"亿", 1e8},
+	{"万", 1e4},
+	{"w", 1e4},
+	{"k", 1e3},
+	{"m", 1e6},
+}
+
+// ParseCount 解析页面展示的数量，支持 "12,345"、"1.2 万"、"3亿"、"3k"、"1.5M"、"10万+" 等格式
+func ParseCount(text string) (int, error) {
+	s := strings.ToLower(strings.TrimSpace(text))
+	s = strings.NewReplacer(",", "", "，", "", " ", "", " ", "").Replace(s)
+	s = strings.TrimSuffix(s, "+")
+	if s == "" {
+		return 0, fmt.Errorf("数量为空")
+	}
+
+	multiplier := 1.0
+	for _, unit := range countUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSuffix(s, unit.suffix)
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	if !countNumber.MatchString(s) {
+		return 0, fmt.Errorf("无法解析数量: %q", text)
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("无法解析数量: %q", text)
+	}
+	// 统计数据保存在 int unsigned 列中，超过 int32 的数量视为解析错误，避免溢出
+	count := math.Round(value * multiplier)
+	if count > math.MaxInt32 {
+		return 0, fmt.Errorf("数量超出范围: %q", text)
+	}
+	return int(count), nil
+}
+
+// parseSignedCount 解析可能带正负号的数量，如新增粉丝数 "-3"、"+12"，其余格式与 ParseCount 相同
+func parseSignedCount(text string) (int, error) {
+	s := strings.TrimSpace(text)
+	sign := 1
+	for _, p := range signPrefixes {
+		if strings.HasPrefix(s, p.prefix) {
+			s, sign = strings.TrimPrefix(s, p.prefix), p.sign
+			break
+		}
+	}
+	count, err := ParseCount(s)
+	if err != nil {
+		return 0, fmt.Errorf("无法解析数量: %q", text)
+	}
+	return sign * count, nil
+}
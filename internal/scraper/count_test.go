@@ -0,0 +1,102 @@
+package scraper
+
+import "testing"
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"0", 0},
+		{"42", 42},
+		{" 123 ", 123},
+		{"12,345", 12345},
+		{"1,234,567", 1234567},
+		{"12，345", 12345},
+		{"1.2k", 1200},
+		{"1.2K", 1200},
+		{"3w", 30000},
+		{"1.5万", 15000},
+		{"1.5 万", 15000},
+		{"10万+", 100000},
+		{"3亿", 300000000},
+		{"1.5M", 1500000},
+		{"99+", 99},
+		{"2147483647", 2147483647},
+		{"21亿", 2100000000},
+	}
+	for _, tt := range tests {
+		got, err := ParseCount(tt.text)
+		if err != nil {
+			t.Errorf("ParseCount(%q) 返回错误: %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCount(%q) = %d，期望 %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseCountInvalid(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"   ",
+		"+",
+		"万",
+		"abc",
+		"阅读",
+		"1.2.3",
+		".5",
+		"1.",
+		"-3",
+		"1e5",
+		"1E5",
+		"0x1p3",
+		"0x10",
+		"Inf",
+		"+Inf",
+		"infinity",
+		"NaN",
+		"1_000",
+		"12 abc",
+		"2147483648",
+		"22亿",
+		"99999999999亿",
+		"1e400",
+	} {
+		if got, err := ParseCount(text); err == nil {
+			t.Errorf("ParseCount(%q) = %d，期望返回错误", text, got)
+		}
+	}
+}
+
+func TestParseSignedCount(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"12", 12},
+		{"+12", 12},
+		{"-3", -3},
+		{" -1,234 ", -1234},
+		{"－5", -5},
+		{"−1.2万", -12000},
+		{"0", 0},
+	}
+	for _, tt := range tests {
+		got, err := parseSignedCount(tt.text)
+		if err != nil {
+			t.Errorf("parseSignedCount(%q) 返回错误: %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseSignedCount(%q) = %d，期望 %d", tt.text, got, tt.want)
+		}
+	}
+
+	for _, text := range []string{"", "+", "-", "--3", "+-3", "- abc", "-2147483648"} {
+		if got, err := parseSignedCount(text); err == nil {
+			t.Errorf("parseSignedCount(%q) = %d，期望返回错误", text, got)
+		}
+	}
+}
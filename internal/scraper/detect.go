@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"context"
+	"crawler/pkg/logger"
+	"fmt"
+	"strings"
+
+	playwright2 "github.com/playwright-community/playwright-go"
+)
+
+// 安全验证页的 URL 特征
+var blockedURLMarkers = []string{"/account/unhuman", "unhuman?", "/captcha"}
+
+// 登录页的 URL 特征
+var loginURLMarkers = []string{"/signin", "/signup", "/login"}
+
+// 安全验证页的文本特征
+var blockedTextMarkers = []string{"安全验证", "系统监测到您的网络环境存在异常", "请完成下方验证", "unhuman"}
+
+// 登录墙的文本特征，仅在页面上没有文章列表或正文时判断，避免把正常页面上的登录弹窗当作登录失效
+var loginTextMarkers = []string{"验证码登录", "密码登录", "扫码登录"}
+
+// 页面已经展示正常内容（文章列表或文章正文）的特征
+const contentMarkerSelector = "div[role='list'], .CreationManage-CreationCard, .Post-RichText, .RichText.ztext"
+
+// DetectBlock 检查当前页面是否为安全验证页或登录页，正常时返回 nil。应在 page.Goto 之后立即调用，
+// 被拦截时不必等待选择器超时
+func DetectBlock(ctx context.Context, page playwright2.Page) error {
+	log := logger.FromContext(ctx).WithModule(logger.ModuleScraper)
+	pageURL := page.URL()
+
+	if containsAny(pageURL, blockedURLMarkers) {
+		return fmt.Errorf("%w: %s", ErrBlocked, pageURL)
+	}
+	if containsAny(pageURL, loginURLMarkers) {
+		return fmt.Errorf("%w: 跳转到 %s", ErrLoggedOut, pageURL)
+	}
+
+	result, err := page.Evaluate(`selector => ({
+		title: document.title || "",
+		text: document.body ? document.body.innerText.slice(0, 3000) : "",
+		hasContent: !!document.querySelector(selector),
+	})`, contentMarkerSelector)
+	if err != nil {
+		// 页面可能仍在跳转，无法判断时不中断任务
+		log.Warn("检测页面状态失败", "url", pageURL, "error", err)
+		return nil
+	}
+
+	info, _ := result.(map[string]interface{})
+	title, _ := info["title"].(string)
+	text, _ := info["text"].(string)
+	hasContent, _ := info["hasContent"].(bool)
+
+	if containsAny(title, blockedTextMarkers) || (!hasContent && containsAny(text, blockedTextMarkers)) {
+		return fmt.Errorf("%w: %s", ErrBlocked, title)
+	}
+	if !hasContent && containsAny(text, loginTextMarkers) {
+		return fmt.Errorf("%w: 页面显示登录表单", ErrLoggedOut)
+	}
+
+	return nil
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, marker := range markers {
+		if strings.Contains(s, marker) {
+			return true
+		}
+	}
+	return false
+}
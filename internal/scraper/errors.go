@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"crawler/pkg/errcode"
+	"errors"
+	"fmt"
+	"strings"
+
+	playwright2 "github.com/playwright-community/playwright-go"
+)
+
+var (
+	// ErrBlocked 页面被知乎安全验证（验证码、网络环境异常）拦截
+	ErrBlocked = errcode.New(errcode.Blocked, "页面被知乎安全验证拦截")
+	// ErrLoggedOut 登录状态失效，页面跳转到了登录页
+	ErrLoggedOut = errcode.New(errcode.NotLoggedIn, "登录状态失效")
+	// ErrSelectorMissing 等待的页面元素没有出现，通常是页面改版
+	ErrSelectorMissing = errcode.New(errcode.SelectorMissing, "页面元素不存在")
+	// ErrTimeout 页面操作超时
+	ErrTimeout = errcode.New(errcode.Timeout, "页面操作超时")
+)
+
+// wrapWaitError 将等待元素的超时归类为元素不存在
+func wrapWaitError(selector string, err error) error {
+	if errors.Is(err, playwright2.ErrTimeout) {
+		return errcode.Wrap(errcode.SelectorMissing, fmt.Sprintf("等待元素 %s 超时", selector), err)
+	}
+	return err
+}
+
+// WrapNavigationError 将页面跳转的超时归类为超时错误，连接失败等浏览器网络错误归类为网络错误
+func WrapNavigationError(url string, err error) error {
+	if errors.Is(err, playwright2.ErrTimeout) {
+		return errcode.Wrap(errcode.Timeout, "访问 "+url+" 超时", err)
+	}
+	if strings.Contains(err.Error(), "net::ERR_") || strings.Contains(err.Error(), "NS_ERROR_") {
+		return errcode.Wrap(errcode.NetworkError, "访问 "+url+" 网络错误", err)
+	}
+	return fmt.Errorf("failed to navigate to %s: %w", url, err)
+}
@@ -0,0 +1,165 @@
+package scraper
+
+import (
+	"context"
+	"crawler/pkg/config"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	playwright2 "github.com/playwright-community/playwright-go"
+)
+
+const (
+	cardSelector  = ".CreationManage-CreationCard"
+	maxWheelSteps = 50 // 单轮滚轮次数上限，避免页面无法滚动时空转
+)
+
+// 一轮滚动结束的原因
+const (
+	settleNewCards    = "new_cards"
+	settleNetworkIdle = "network_idle"
+	settleTimeout     = "timeout"
+)
+
+// pacer 模拟人工浏览的滚动节奏：滚轮分多次小步滚动，每轮之间随机停顿，
+// 滚动后等到新卡片出现或网络空闲即继续，不再固定等待
+type pacer struct {
+	page    playwright2.Page
+	config  config.PacingConfig
+	network *networkTracker
+}
+
+func newPacer(page playwright2.Page, cfg config.PacingConfig) *pacer {
+	return &pacer{
+		page:    page,
+		config:  cfg.WithDefaults(),
+		network: trackNetwork(page),
+	}
+}
+
+// scroll 把鼠标移到页面中部后用滚轮分步滚到底部，返回滚轮次数
+func (p *pacer) scroll(ctx context.Context) (int, error) {
+	if size := p.page.ViewportSize(); size != nil {
+		x := float64(size.Width) * (0.3 + rand.Float64()*0.4)
+		y := float64(size.Height) * (0.3 + rand.Float64()*0.4)
+		if err := p.page.Mouse().Move(x, y, playwright2.MouseMoveOptions{Steps: playwright2.Int(5 + rand.IntN(10))}); err != nil {
+			return 0, err
+		}
+	}
+
+	steps := 0
+	for steps < maxWheelSteps {
+		step := float64(p.config.ScrollStep) * (0.7 + rand.Float64()*0.6)
+		if err := p.page.Mouse().Wheel(0, step); err != nil {
+			return steps, err
+		}
+		steps++
+
+		if err := sleep(ctx, randomDuration(p.config.StepMinDelay, p.config.StepMaxDelay)); err != nil {
+			return steps, err
+		}
+
+		atBottom, err := p.page.Evaluate(`() => window.scrollY + window.innerHeight >= document.body.scrollHeight - 10`)
+		if err != nil {
+			return steps, err
+		}
+		if bottom, _ := atBottom.(bool); bottom {
+			return steps, nil
+		}
+	}
+	return steps, nil
+}
+
+// settle 等待新卡片出现或网络空闲，最长等待 SettleTimeout，返回结束原因
+func (p *pacer) settle(ctx context.Context, previousCards int) (string, error) {
+	start := time.Now()
+	deadline := start.Add(p.config.SettleTimeout)
+	cards := p.page.Locator(cardSelector)
+	for {
+		count, err := cards.Count()
+		if err != nil {
+			return "", err
+		}
+		if count > previousCards {
+			return settleNewCards, nil
+		}
+		if p.network.idleSince(start) >= p.config.NetworkIdle {
+			return settleNetworkIdle, nil
+		}
+		if time.Now().After(deadline) {
+			return settleTimeout, nil
+		}
+		if err := sleep(ctx, 200*time.Millisecond); err != nil {
+			return "", err
+		}
+	}
+}
+
+// pause 每轮滚动之间的随机停顿
+func (p *pacer) pause(ctx context.Context) error {
+	return sleep(ctx, randomDuration(p.config.MinDelay, p.config.MaxDelay))
+}
+
+// Pause 两次页面访问之间的随机停顿，时长与滚动间隔相同
+func Pause(ctx context.Context, cfg config.PacingConfig) error {
+	cfg = cfg.WithDefaults()
+	return sleep(ctx, randomDuration(cfg.MinDelay, cfg.MaxDelay))
+}
+
+// networkTracker 统计页面进行中的请求数和最近一次请求活动的时间
+type networkTracker struct {
+	mu           sync.Mutex
+	inflight     int
+	lastActivity time.Time
+}
+
+func trackNetwork(page playwright2.Page) *networkTracker {
+	t := &networkTracker{lastActivity: time.Now()}
+	page.OnRequest(func(playwright2.Request) { t.update(1) })
+	page.OnRequestFinished(func(playwright2.Request) { t.update(-1) })
+	page.OnRequestFailed(func(playwright2.Request) { t.update(-1) })
+	return t
+}
+
+func (t *networkTracker) update(delta int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.inflight += delta
+	if t.inflight < 0 {
+		t.inflight = 0
+	}
+	t.lastActivity = time.Now()
+}
+
+// idleSince 从 start 起没有进行中的请求持续的时间，有请求进行中时为 0
+func (t *networkTracker) idleSince(start time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.inflight > 0 {
+		return 0
+	}
+	if t.lastActivity.After(start) {
+		start = t.lastActivity
+	}
+	return time.Since(start)
+}
+
+func randomDuration(lo, hi time.Duration) time.Duration {
+	if hi <= lo {
+		return lo
+	}
+	return lo + rand.N(hi-lo)
+}
+
+// sleep 可被取消的等待
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
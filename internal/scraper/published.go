@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"crawler/pkg/timezone"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Shanghai 知乎页面展示时间使用的时区
+var Shanghai = timezone.Shanghai
+
+var (
+	publishedPrefixes = []string{"发布于", "编辑于", "发表于", "创建于", "最近编辑", "修改于"}
+
+	relativePattern = regexp.MustCompile(`^(\d+)\s*(秒|分钟|小时|天|周|个月|月|年)前$`)
+	dayPattern      = regexp.MustCompile(`^(今天|昨天|前天)\s*(\d{1,2}:\d{2})?$`)
+	clockPattern    = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+)
+
+// 绝对时间格式，不带年份的按当前年份处理
+var (
+	absoluteLayouts = []string{
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		"2006-01-02",
+		"2006/01/02 15:04",
+		"2006/01/02",
+		"2006年01月02日 15:04",
+		"2006年1月2日 15:04",
+		"2006年01月02日",
+		"2006年1月2日",
+	}
+	noYearLayouts = []string{
+		"01-02 15:04",
+		"01-02",
+		"1月2日 15:04",
+		"01月02日 15:04",
+		"1月2日",
+		"01月02日",
+	}
+)
+
+// ParsePublishedTime 解析知乎展示的发布时间，相对时间以 now 为基准，按 Asia/Shanghai 时区解析。
+// 支持 "刚刚"、"3 小时前"、"昨天 12:30"、"12:30"、"12-10 14:22"、"2024-12-10" 等格式，
+// 可带 "发布于"、"编辑于" 等前缀
+func ParsePublishedTime(raw string, now time.Time) (time.Time, error) {
+	text := strings.TrimSpace(raw)
+	for _, prefix := range publishedPrefixes {
+		text = strings.TrimSpace(strings.TrimPrefix(text, prefix))
+	}
+	if text == "" {
+		return time.Time{}, fmt.Errorf("发布时间为空")
+	}
+
+	now = now.In(Shanghai)
+	if text == "刚刚" {
+		return now, nil
+	}
+
+	if m := relativePattern.FindStringSubmatch(text); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "秒":
+			return now.Add(-time.Duration(n) * time.Second), nil
+		case "分钟":
+			return now.Add(-time.Duration(n) * time.Minute), nil
+		case "小时":
+			return now.Add(-time.Duration(n) * time.Hour), nil
+		case "天":
+			return now.AddDate(0, 0, -n), nil
+		case "周":
+			return now.AddDate(0, 0, -7*n), nil
+		case "个月", "月":
+			return now.AddDate(0, -n, 0), nil
+		case "年":
+			return now.AddDate(-n, 0, 0), nil
+		}
+	}
+
+	if m := dayPattern.FindStringSubmatch(text); m != nil {
+		day := map[string]int{"今天": 0, "昨天": 1, "前天": 2}[m[1]]
+		date := now.AddDate(0, 0, -day)
+		clock := m[2]
+		if clock == "" {
+			clock = "00:00"
+		}
+		return atClock(date, clock)
+	}
+
+	// 只有时间的默认为今天
+	if clockPattern.MatchString(text) {
+		return atClock(now, text)
+	}
+
+	for _, layout := range absoluteLayouts {
+		if t, err := time.ParseInLocation(layout, text, Shanghai); err == nil {
+			return t, nil
+		}
+	}
+	for _, layout := range noYearLayouts {
+		if t, err := time.ParseInLocation(layout, text, Shanghai); err == nil {
+			// 取不晚于当前时间的最近一年，日期晚于当前时间或今年没有这一天（平年的 02-29）时为去年
+			for _, year := range []int{now.Year(), now.Year() - 1} {
+				if d, ok := inYear(t, year); ok && !d.After(now) {
+					return d, nil
+				}
+			}
+			return time.Time{}, fmt.Errorf("无法确定发布时间的年份: %q", raw)
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("无法解析发布时间: %q", raw)
+}
+
+// inYear 返回 t 的月日时分在 year 年的时间，这一天在该年不存在时返回 false
+func inYear(t time.Time, year int) (time.Time, bool) {
+	d := time.Date(year, t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, Shanghai)
+	return d, d.Month() == t.Month()
+}
+
+// atClock 返回 date 当天 clock（HH:MM）时刻
+func atClock(date time.Time, clock string) (time.Time, error) {
+	t, err := time.ParseInLocation("15:04", clock, Shanghai)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("无法解析时间 %q: %w", clock, err)
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, Shanghai), nil
+}
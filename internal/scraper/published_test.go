@@ -0,0 +1,83 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParsePublishedTime(t *testing.T) {
+	at := func(year int, month time.Month, day, hour, min int) time.Time {
+		return time.Date(year, month, day, hour, min, 0, 0, Shanghai)
+	}
+	// 北京时间 2025-03-10 14:00
+	now := time.Date(2025, 3, 10, 6, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		raw  string
+		want time.Time
+	}{
+		{"刚刚", at(2025, 3, 10, 14, 0)},
+		{"30 秒前", at(2025, 3, 10, 13, 59).Add(30 * time.Second)},
+		{"5分钟前", at(2025, 3, 10, 13, 55)},
+		{"3 小时前", at(2025, 3, 10, 11, 0)},
+		{"2 天前", at(2025, 3, 8, 14, 0)},
+		{"1 周前", at(2025, 3, 3, 14, 0)},
+		{"2 个月前", at(2025, 1, 10, 14, 0)},
+		{"1 年前", at(2024, 3, 10, 14, 0)},
+		{"今天 08:15", at(2025, 3, 10, 8, 15)},
+		{"昨天 12:30", at(2025, 3, 9, 12, 30)},
+		{"前天", at(2025, 3, 8, 0, 0)},
+		{"09:05", at(2025, 3, 10, 9, 5)},
+		{"发布于 昨天 23:59", at(2025, 3, 9, 23, 59)},
+		{"编辑于 2024-12-10 14:22", at(2024, 12, 10, 14, 22)},
+		{"  发布于 3 小时前 ", at(2025, 3, 10, 11, 0)},
+		{"2024-12-10", at(2024, 12, 10, 0, 0)},
+		{"2024/12/10 08:00", at(2024, 12, 10, 8, 0)},
+		{"2024年1月2日 15:04", at(2024, 1, 2, 15, 4)},
+		// 不带年份的日期
+		{"03-01 10:00", at(2025, 3, 1, 10, 0)},
+		{"03-10 13:59", at(2025, 3, 10, 13, 59)},
+		{"03-10 14:01", at(2024, 3, 10, 14, 1)},
+		{"12-31", at(2024, 12, 31, 0, 0)},
+		{"3月20日", at(2024, 3, 20, 0, 0)},
+		{"发布于 06月01日 09:00", at(2024, 6, 1, 9, 0)},
+		// 2025 年是平年，02-29 只能是 2024 年
+		{"02-29", at(2024, 2, 29, 0, 0)},
+	}
+	for _, tt := range tests {
+		got, err := ParsePublishedTime(tt.raw, now)
+		if err != nil {
+			t.Errorf("ParsePublishedTime(%q) 返回错误: %v", tt.raw, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParsePublishedTime(%q) = %v，期望 %v", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestParsePublishedTimeLeapDay(t *testing.T) {
+	// 2024 年是闰年，当前时间在 02-29 之前时 02-29 不能是今年，去年（2023）又没有这一天
+	now := time.Date(2024, 2, 10, 12, 0, 0, 0, Shanghai)
+	if got, err := ParsePublishedTime("02-29", now); err == nil {
+		t.Errorf("ParsePublishedTime(%q) = %v，期望返回错误", "02-29", got)
+	}
+
+	now = time.Date(2024, 3, 1, 12, 0, 0, 0, Shanghai)
+	got, err := ParsePublishedTime("02-29 08:00", now)
+	if err != nil {
+		t.Fatalf("ParsePublishedTime 返回错误: %v", err)
+	}
+	if want := time.Date(2024, 2, 29, 8, 0, 0, 0, Shanghai); !got.Equal(want) {
+		t.Errorf("ParsePublishedTime = %v，期望 %v", got, want)
+	}
+}
+
+func TestParsePublishedTimeInvalid(t *testing.T) {
+	now := time.Date(2025, 3, 10, 14, 0, 0, 0, Shanghai)
+	for _, raw := range []string{"", "发布于", "很久以前", "02-30", "13-01", "25:00", "明天 10:00"} {
+		if got, err := ParsePublishedTime(raw, now); err == nil {
+			t.Errorf("ParsePublishedTime(%q) = %v，期望返回错误", raw, got)
+		}
+	}
+}
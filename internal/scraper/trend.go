@@ -0,0 +1,207 @@
+package scraper
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 趋势数据中表示日期的字段
+var trendDateKeys = map[string]bool{"date": true, "p_date": true, "day": true, "data_date": true, "stat_date": true}
+
+// 趋势数据字段按名称关键字识别，接口字段不固定时仍能提取
+var (
+	trendReadKeys     = []string{"pv", "read", "view"}
+	trendUpvoteKeys   = []string{"upvote", "voteup", "approve"}
+	trendFollowerKeys = []string{"follow", "fans"}
+)
+
+// parseTrend 从创作中心接口响应中提取账号的每日数据。趋势数据是一个数组，每个元素是带日期字段和至少一项数据的对象，
+// 且日期互不相同；带内容标识（如 id、title、url）的对象或日期重复的数组是单篇内容的数据，不视为账号数据。
+// 同一天出现在多个响应中时合并各项数据，同一项数据以先出现的为准。无法解析的响应直接忽略，返回按日期升序的结果
+func parseTrend(bodies [][]byte) []DailyMetrics {
+	days := make(map[time.Time]*trendDay)
+	for _, body := range bodies {
+		var data interface{}
+		if err := json.Unmarshal(body, &data); err != nil {
+			continue
+		}
+		walkTrend(data, days)
+	}
+
+	trend := make([]DailyMetrics, 0, len(days))
+	for _, day := range days {
+		trend = append(trend, day.DailyMetrics)
+	}
+	sort.Slice(trend, func(i, j int) bool { return trend[i].Date.Before(trend[j].Date) })
+	return trend
+}
+
+// walkTrend 查找趋势数组并合并到 days，对象的字段按名称顺序遍历，保证结果与 map 的遍历顺序无关
+func walkTrend(value interface{}, days map[time.Time]*trendDay) {
+	switch v := value.(type) {
+	case []interface{}:
+		if series, ok := trendSeries(v); ok {
+			for _, day := range series {
+				mergeTrendDay(days, day)
+			}
+			return
+		}
+		for _, item := range v {
+			walkTrend(item, days)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			walkTrend(v[key], days)
+		}
+	}
+}
+
+// trendSeries 数组的每个元素都是一天的账号数据且日期互不相同时返回这些数据
+func trendSeries(items []interface{}) ([]trendDay, bool) {
+	if len(items) == 0 {
+		return nil, false
+	}
+	series := make([]trendDay, 0, len(items))
+	seen := make(map[time.Time]bool, len(items))
+	for _, item := range items {
+		row, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		day, ok := trendRow(row)
+		if !ok || seen[day.Date] {
+			return nil, false
+		}
+		seen[day.Date] = true
+		series = append(series, day)
+	}
+	return series, true
+}
+
+// trendDay 一天的数据和其中出现的数据项
+type trendDay struct {
+	DailyMetrics
+	reads, upvotes, followers bool
+}
+
+// trendRow 解析一天的账号数据，对象没有日期、没有数据项或带内容标识时返回 false
+func trendRow(row map[string]interface{}) (trendDay, bool) {
+	var date time.Time
+	for key, value := range row {
+		key = strings.ToLower(key)
+		if trendContentKey(key) {
+			return trendDay{}, false
+		}
+		if trendDateKeys[key] {
+			if t, ok := parseTrendDate(value); ok {
+				date = t
+			}
+		}
+	}
+	if date.IsZero() {
+		return trendDay{}, false
+	}
+
+	day := trendDay{DailyMetrics: DailyMetrics{Date: date}}
+	for key, value := range row {
+		count, ok := trendCount(value)
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(key)
+		switch {
+		case strings.Contains(key, "rate"), strings.Contains(key, "ratio"):
+			// 比率不是计数
+		case containsAny(key, trendUpvoteKeys): // upvote 包含 pv，需要先于阅读数判断
+			day.Upvotes, day.upvotes = count, true
+		case containsAny(key, trendFollowerKeys):
+			day.Followers, day.followers = count, true
+		case containsAny(key, trendReadKeys):
+			day.Reads, day.reads = count, true
+		}
+	}
+	return day, day.reads || day.upvotes || day.followers
+}
+
+// trendContentKey 判断字段是否为单篇内容的标识，带这些字段的对象不是账号数据
+func trendContentKey(key string) bool {
+	switch key {
+	case "id", "token", "title", "url", "excerpt":
+		return true
+	}
+	return strings.HasSuffix(key, "_id") || strings.HasSuffix(key, "_token") ||
+		strings.HasSuffix(key, "_url") || strings.HasSuffix(key, "_title")
+}
+
+// mergeTrendDay 将一天的数据合并到 days，已有的数据项不覆盖
+func mergeTrendDay(days map[time.Time]*trendDay, day trendDay) {
+	existing, ok := days[day.Date]
+	if !ok {
+		days[day.Date] = &day
+		return
+	}
+	if day.reads && !existing.reads {
+		existing.Reads, existing.reads = day.Reads, true
+	}
+	if day.upvotes && !existing.upvotes {
+		existing.Upvotes, existing.upvotes = day.Upvotes, true
+	}
+	if day.followers && !existing.followers {
+		existing.Followers, existing.followers = day.Followers, true
+	}
+}
+
+// parseTrendDate 解析 2006-01-02 或 20060102 格式的日期
+func parseTrendDate(value interface{}) (time.Time, bool) {
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = strings.TrimSpace(v)
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return time.Time{}, false
+	}
+	for _, layout := range []string{time.DateOnly, "20060102"} {
+		if t, err := time.ParseInLocation(layout, text, Shanghai); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// trendCount 接受整数或整数字符串，新增粉丝数可能为负数
+func trendCount(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt32 {
+			return 0, false
+		}
+		return int(v), true
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// mergeDaily 趋势数据中没有 day 当天的数据时按日期顺序插入
+func mergeDaily(trend []DailyMetrics, day DailyMetrics) []DailyMetrics {
+	i := sort.Search(len(trend), func(i int) bool { return !trend[i].Date.Before(day.Date) })
+	if i < len(trend) && trend[i].Date.Equal(day.Date) {
+		return trend
+	}
+	trend = append(trend, DailyMetrics{})
+	copy(trend[i+1:], trend[i:])
+	trend[i] = day
+	return trend
+}
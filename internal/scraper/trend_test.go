@@ -0,0 +1,112 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDescendants(t *testing.T) {
+	got := descendants([]string{"[class*='DataOverview']", "[class*='Overview']"})
+	want := "[class*='DataOverview'] *, [class*='Overview'] *"
+	if got != want {
+		t.Errorf("descendants = %q，期望 %q", got, want)
+	}
+}
+
+func TestParseTrend(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 12, d, 0, 0, 0, 0, Shanghai) }
+	tests := []struct {
+		name   string
+		bodies []string
+		want   []DailyMetrics
+	}{
+		{
+			"嵌套的每日数据",
+			[]string{`{"data": {"list": [
+				{"date": "2024-12-02", "pv": 120, "upvote": 3, "new_follow": -1},
+				{"date": "2024-12-01", "pv": 100, "upvote": 5, "new_follow": 2, "upvote_rate": 0.05}
+			]}}`},
+			[]DailyMetrics{{day(1), 100, 5, 2}, {day(2), 120, 3, -1}},
+		},
+		{
+			"多个响应合并同一天的数据",
+			[]string{
+				`[{"p_date": "20241201", "read_count": "100"}]`,
+				`{"items": [{"p_date": 20241201, "voteup_count": 5, "fans_incr": 2}]}`,
+			},
+			[]DailyMetrics{{day(1), 100, 5, 2}},
+		},
+		{
+			"单篇内容的数据不视为账号数据",
+			[]string{
+				`{"data": [
+					{"date": "2024-12-01", "pv": 999, "content_id": "1"},
+					{"date": "2024-12-01", "pv": 888, "content_id": "2"}
+				]}`,
+				`{"list": [{"date": "2024-12-01", "pv": 7, "title": "文章"}]}`,
+				`{"rows": [{"date": "2024-12-01", "pv": 5}, {"date": "2024-12-01", "pv": 6}]}`,
+				`{"trend": [{"date": "2024-12-01", "pv": 100}]}`,
+			},
+			[]DailyMetrics{{day(1), 100, 0, 0}},
+		},
+		{
+			"同一项数据以先出现的为准",
+			[]string{
+				`{"b": [{"date": "2024-12-01", "pv": 2}], "a": [{"date": "2024-12-01", "pv": 1}]}`,
+				`[{"date": "2024-12-01", "pv": 3, "upvote": 4}]`,
+			},
+			[]DailyMetrics{{day(1), 1, 4, 0}},
+		},
+		{
+			"忽略无法识别的数据",
+			[]string{
+				`not json`,
+				`{"date": "2024-12-01"}`,
+				`{"date": "yesterday", "pv": 1}`,
+				`{"date": "2024-12-01", "pv": 1.5}`,
+				`{"date": "2024-12-01", "pv": 1}`,
+				`[{"date": "2024-12-01", "pv": 1}, "x"]`,
+				`{"member": {"name": "x", "follower_count": 10}}`,
+			},
+			[]DailyMetrics{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodies := make([][]byte, len(tt.bodies))
+			for i, body := range tt.bodies {
+				bodies[i] = []byte(body)
+			}
+			got := parseTrend(bodies)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseTrend = %+v，期望 %+v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Date.Equal(tt.want[i].Date) || got[i].Reads != tt.want[i].Reads ||
+					got[i].Upvotes != tt.want[i].Upvotes || got[i].Followers != tt.want[i].Followers {
+					t.Errorf("第 %d 天 = %+v，期望 %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMergeDaily(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 12, d, 0, 0, 0, 0, Shanghai) }
+	trend := []DailyMetrics{{Date: day(1), Reads: 1}, {Date: day(3), Reads: 3}}
+
+	got := mergeDaily(trend, DailyMetrics{Date: day(2), Reads: 2})
+	if len(got) != 3 || !got[1].Date.Equal(day(2)) || got[1].Reads != 2 || !got[2].Date.Equal(day(3)) {
+		t.Errorf("插入中间日期 = %+v", got)
+	}
+
+	got = mergeDaily([]DailyMetrics{{Date: day(1), Reads: 1}}, DailyMetrics{Date: day(1), Reads: 9})
+	if len(got) != 1 || got[0].Reads != 1 {
+		t.Errorf("已有当天数据时应保留趋势数据，得到 %+v", got)
+	}
+
+	got = mergeDaily(nil, DailyMetrics{Date: day(5), Reads: 5})
+	if len(got) != 1 || got[0].Reads != 5 {
+		t.Errorf("空趋势 = %+v", got)
+	}
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"crawler/internal/progress"
+	"crawler/internal/repository"
+	"crawler/internal/scraper"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"fmt"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// crawlAccountMetrics 抓取创作中心首页的账号数据并保存快照，失败只记录警告，不影响任务结果
+func (s *CrawlerService) crawlAccountMetrics(ctx context.Context, page playwright.Page, run *repository.CrawlRun) error {
+	if !s.config.Account.Enabled {
+		return nil
+	}
+	log := logger.FromContext(ctx)
+
+	data, err := scraper.ExtractAccountMetrics(ctx, page, s.config.Retry)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Warn("抓取账号数据失败，跳过", "reason", errcode.Of(err).String(), "error", err)
+		s.captureArtifacts(ctx, page, run.ID, "account_metrics_failed")
+		return nil
+	}
+
+	snapshot := &repository.AccountMetricsSnapshot{
+		Account:        run.Account,
+		CrawlRunID:     run.ID,
+		Followers:      data.Followers,
+		TotalReads:     data.TotalReads,
+		TotalUpvotes:   data.TotalUpvotes,
+		DailyReads:     data.DailyReads,
+		DailyUpvotes:   data.DailyUpvotes,
+		DailyFollowers: data.DailyFollowers,
+		DataDate:       data.DataDate,
+		CapturedAt:     time.Now(),
+	}
+	if err := s.accounts.Create(ctx, snapshot); err != nil {
+		log.Warn("保存账号数据失败", "error", err)
+		return nil
+	}
+
+	daily := make([]repository.AccountDailyMetric, len(data.Trend))
+	for i, day := range data.Trend {
+		daily[i] = repository.AccountDailyMetric{
+			Account:    run.Account,
+			Date:       day.Date,
+			Reads:      day.Reads,
+			Upvotes:    day.Upvotes,
+			Followers:  day.Followers,
+			CrawlRunID: run.ID,
+		}
+	}
+	if err := s.accounts.UpsertDaily(ctx, daily); err != nil {
+		log.Warn("保存账号每日数据失败", "error", err)
+	}
+
+	log.Info("账号数据抓取完成",
+		"followers", data.Followers,
+		"total_reads", data.TotalReads,
+		"total_upvotes", data.TotalUpvotes,
+		"trend_days", len(data.Trend),
+	)
+	progress.Report(ctx, progress.EventAccountMetrics, map[string]interface{}{
+		"followers":     data.Followers,
+		"total_reads":   data.TotalReads,
+		"total_upvotes": data.TotalUpvotes,
+		"trend_days":    len(data.Trend),
+	})
+	return nil
+}
+
+type IAccountService interface {
+	Metrics(ctx context.Context, account string, from, to time.Time) ([]repository.AccountMetricsSnapshot, error)
+	Daily(ctx context.Context, account string, from, to time.Time) ([]repository.AccountDailyMetric, error)
+}
+
+type AccountService struct {
+	metrics repository.AccountMetricsRepository
+}
+
+func NewAccountService(metrics repository.AccountMetricsRepository) IAccountService {
+	return &AccountService{
+		metrics: metrics,
+	}
+}
+
+// Metrics 按抓取时间返回账号在 [from, to) 内的数据快照，零值表示不限制
+func (s *AccountService) Metrics(ctx context.Context, account string, from, to time.Time) ([]repository.AccountMetricsSnapshot, error) {
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, errcode.New(errcode.InvalidRequest, "from 必须早于 to")
+	}
+	snapshots, err := s.metrics.FindByAccount(ctx, account, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("查询账号数据失败: %w", err)
+	}
+	return snapshots, nil
+}
+
+// Daily 按日期返回账号在 [from, to) 内的每日数据，零值表示不限制
+func (s *AccountService) Daily(ctx context.Context, account string, from, to time.Time) ([]repository.AccountDailyMetric, error) {
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return nil, errcode.New(errcode.InvalidRequest, "from 必须早于 to")
+	}
+	metrics, err := s.metrics.FindDaily(ctx, account, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("查询账号每日数据失败: %w", err)
+	}
+	return metrics, nil
+}
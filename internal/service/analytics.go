@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"crawler/internal/repository"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/retry"
+	"fmt"
+	"time"
+)
+
+// 排行和列表的默认条数和上限
+const (
+	defaultAnalyticsLimit = 10
+	maxAnalyticsLimit     = 100
+)
+
+// snapshotStats 保存全部文章当天的统计数据快照，用于计算每日变化，失败只记录警告。
+// 增量或不完整的爬取只更新了部分文章的统计数据，其余文章的数据是旧的，写入快照会让每日变化失真，
+// 因此只在完整的全量爬取后保存
+func (s *CrawlerService) snapshotStats(ctx context.Context, run *repository.CrawlRun, complete bool) error {
+	if !complete {
+		logger.FromContext(ctx).Debug("本次爬取不完整，跳过统计快照", "mode", run.Mode)
+		return nil
+	}
+	var rows int64
+	err := retry.Do(ctx, s.config.Retry, retry.OpDBSnapshot, func(int) (err error) {
+		rows, err = s.analytics.SnapshotStats(ctx, run.ID, time.Now())
+		return err
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger.FromContext(ctx).Warn("保存文章统计快照失败", "error", err)
+		return nil
+	}
+	logger.FromContext(ctx).Debug("保存文章统计快照", "rows", rows)
+	return nil
+}
+
+// EngagementReport 互动率排行和整体互动率
+type EngagementReport struct {
+	Summary  *repository.Engagement
+	Articles []repository.Engagement
+}
+
+type IAnalyticsService interface {
+	TopArticles(ctx context.Context, stat string, from, to time.Time, limit int) ([]repository.RankedArticle, error)
+	DailyStats(ctx context.Context, articleID int64, from, to time.Time) ([]repository.DailyStats, error)
+	Engagement(ctx context.Context, order string, minReads, limit int) (*EngagementReport, error)
+	PublishingCadence(ctx context.Context, from, to time.Time) ([]repository.MonthlyCadence, error)
+}
+
+type AnalyticsService struct {
+	analytics repository.AnalyticsRepository
+}
+
+func NewAnalyticsService(analytics repository.AnalyticsRepository) IAnalyticsService {
+	return &AnalyticsService{
+		analytics: analytics,
+	}
+}
+
+// TopArticles 按统计项返回排名靠前的文章，指定时间窗口时按窗口内的增量排序
+func (s *AnalyticsService) TopArticles(ctx context.Context, stat string, from, to time.Time, limit int) ([]repository.RankedArticle, error) {
+	if stat == "" {
+		stat = repository.StatReads
+	}
+	if !repository.ValidStat(stat) {
+		return nil, errcode.New(errcode.InvalidRequest, "不支持的统计项: "+stat)
+	}
+	if err := checkWindow(from, to); err != nil {
+		return nil, err
+	}
+	limit, err := analyticsLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	articles, err := s.analytics.TopArticles(ctx, stat, from, to, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询文章排行失败: %w", err)
+	}
+	return articles, nil
+}
+
+// DailyStats 返回每日统计数据合计和与上一个快照日相比的变化，articleID 不为 0 时只统计该文章
+func (s *AnalyticsService) DailyStats(ctx context.Context, articleID int64, from, to time.Time) ([]repository.DailyStats, error) {
+	if err := checkWindow(from, to); err != nil {
+		return nil, err
+	}
+	stats, err := s.analytics.DailyStats(ctx, articleID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("查询每日统计失败: %w", err)
+	}
+	return stats, nil
+}
+
+// Engagement 返回互动率排行和整体互动率，只统计阅读数不少于 minReads 的文章
+func (s *AnalyticsService) Engagement(ctx context.Context, order string, minReads, limit int) (*EngagementReport, error) {
+	if order == "" {
+		order = repository.RateUpvote
+	}
+	if order != repository.RateUpvote && order != repository.RateBookmark {
+		return nil, errcode.New(errcode.InvalidRequest, "不支持的排序方式: "+order)
+	}
+	// 阅读数为 0 时比率没有意义
+	if minReads < 1 {
+		minReads = 1
+	}
+	limit, err := analyticsLimit(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.analytics.EngagementSummary(ctx, minReads)
+	if err != nil {
+		return nil, fmt.Errorf("查询整体互动率失败: %w", err)
+	}
+	articles, err := s.analytics.Engagement(ctx, order, minReads, limit)
+	if err != nil {
+		return nil, fmt.Errorf("查询互动率排行失败: %w", err)
+	}
+	return &EngagementReport{Summary: summary, Articles: articles}, nil
+}
+
+// PublishingCadence 返回每月发布的文章数，首末月份之间没有发布的月份补 0
+func (s *AnalyticsService) PublishingCadence(ctx context.Context, from, to time.Time) ([]repository.MonthlyCadence, error) {
+	if err := checkWindow(from, to); err != nil {
+		return nil, err
+	}
+	months, err := s.analytics.PublishingCadence(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("查询发布节奏失败: %w", err)
+	}
+	return fillMonths(months), nil
+}
+
+// fillMonths 在按月份排序的结果中补全缺少的月份
+func fillMonths(months []repository.MonthlyCadence) []repository.MonthlyCadence {
+	if len(months) < 2 {
+		return months
+	}
+	result := make([]repository.MonthlyCadence, 0, len(months))
+	for _, m := range months {
+		if len(result) > 0 {
+			last, err1 := time.Parse("2006-01", result[len(result)-1].Month)
+			current, err2 := time.Parse("2006-01", m.Month)
+			if err1 == nil && err2 == nil {
+				for next := last.AddDate(0, 1, 0); next.Before(current); next = next.AddDate(0, 1, 0) {
+					result = append(result, repository.MonthlyCadence{Month: next.Format("2006-01")})
+				}
+			}
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+func checkWindow(from, to time.Time) error {
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return errcode.New(errcode.InvalidRequest, "to 不能早于 from")
+	}
+	return nil
+}
+
+func analyticsLimit(limit int) (int, error) {
+	switch {
+	case limit == 0:
+		return defaultAnalyticsLimit, nil
+	case limit < 0 || limit > maxAnalyticsLimit:
+		return 0, errcode.New(errcode.InvalidRequest, fmt.Sprintf("limit 必须在 1-%d 之间", maxAnalyticsLimit))
+	}
+	return limit, nil
+}
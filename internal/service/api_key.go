@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"crawler/internal/repository"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 权限范围
+const (
+	ScopeCrawlTrigger = "crawl:trigger"
+	ScopeArticlesRead = "articles:read"
+	ScopeExportRun    = "export:run"
+	ScopeAdmin        = "admin"
+)
+
+// AllScopes 全部可分配的权限范围
+var AllScopes = []string{ScopeCrawlTrigger, ScopeArticlesRead, ScopeExportRun, ScopeAdmin}
+
+const apiKeyPrefix = "zk_"
+
+// lastUsedInterval 最后使用时间的更新间隔，避免每次请求都写数据库
+const lastUsedInterval = time.Minute
+
+var (
+	ErrInvalidAPIKey = errcode.New(errcode.Unauthorized, "API密钥无效")
+	ErrAPIKeyExpired = errcode.New(errcode.Unauthorized, "API密钥已过期")
+)
+
+// Principal 已认证的调用方
+type Principal struct {
+	KeyID  int64
+	Name   string
+	Scopes []string
+}
+
+// HasScope 判断调用方是否拥有指定权限，admin 拥有全部权限
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+type IAPIKeyService interface {
+	CreateKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (string, *repository.APIKey, error)
+	ListKeys(ctx context.Context) ([]repository.APIKey, error)
+	DeleteKey(ctx context.Context, id int64) (bool, error)
+	Authenticate(ctx context.Context, rawKey string) (*Principal, error)
+}
+
+type APIKeyService struct {
+	repository repository.APIKeyRepository
+}
+
+func NewAPIKeyService(repo repository.APIKeyRepository) IAPIKeyService {
+	return &APIKeyService{
+		repository: repo,
+	}
+}
+
+// CreateKey 生成新的 API 密钥，明文密钥只在此处返回一次
+func (s *APIKeyService) CreateKey(ctx context.Context, name string, scopes []string, expiresAt *time.Time) (string, *repository.APIKey, error) {
+	if strings.TrimSpace(name) == "" {
+		return "", nil, errcode.New(errcode.InvalidRequest, "密钥名称不能为空")
+	}
+	if len(scopes) == 0 {
+		return "", nil, errcode.New(errcode.InvalidRequest, "至少需要一个权限范围")
+	}
+	for _, scope := range scopes {
+		if !isKnownScope(scope) {
+			return "", nil, errcode.New(errcode.InvalidRequest, "未知的权限范围: "+scope)
+		}
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return "", nil, errcode.New(errcode.InvalidRequest, "过期时间必须晚于当前时间")
+	}
+
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, fmt.Errorf("生成密钥失败: %w", err)
+	}
+	rawKey := apiKeyPrefix + hex.EncodeToString(buf)
+
+	key := &repository.APIKey{
+		Name:      name,
+		Prefix:    rawKey[:len(apiKeyPrefix)+6],
+		KeyHash:   hashKey(rawKey),
+		Scopes:    strings.Join(scopes, ","),
+		ExpiresAt: expiresAt,
+	}
+	if err := s.repository.Create(ctx, key); err != nil {
+		return "", nil, fmt.Errorf("保存密钥失败: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("创建API密钥",
+		"key_id", key.ID,
+		"name", key.Name,
+		"scopes", key.Scopes,
+	)
+	return rawKey, key, nil
+}
+
+func (s *APIKeyService) ListKeys(ctx context.Context) ([]repository.APIKey, error) {
+	return s.repository.FindAll(ctx)
+}
+
+func (s *APIKeyService) DeleteKey(ctx context.Context, id int64) (bool, error) {
+	deleted, err := s.repository.Delete(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if deleted {
+		logger.FromContext(ctx).Info("删除API密钥", "key_id", id)
+	}
+	return deleted, nil
+}
+
+// Authenticate 校验明文密钥，距离上次记录超过 lastUsedInterval 时更新最后使用时间
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey string) (*Principal, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.repository.FindByHash(ctx, hashKey(rawKey))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidAPIKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询密钥失败: %w", err)
+	}
+
+	now := time.Now()
+	if key.ExpiresAt != nil && !key.ExpiresAt.After(now) {
+		return nil, ErrAPIKeyExpired
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= lastUsedInterval {
+		if err := s.repository.TouchLastUsed(ctx, key.ID, now); err != nil {
+			logger.FromContext(ctx).Warn("更新密钥使用时间失败", "key_id", key.ID, "error", err)
+		}
+	}
+
+	return &Principal{
+		KeyID:  key.ID,
+		Name:   key.Name,
+		Scopes: strings.Split(key.Scopes, ","),
+	}, nil
+}
+
+func hashKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func isKnownScope(scope string) bool {
+	for _, s := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"crawler/internal/repository"
+	"crawler/pkg/errcode"
+	"errors"
+	"fmt"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrArticleNotFound  = errcode.New(errcode.NotFound, "文章不存在")
+	ErrRevisionNotFound = errcode.New(errcode.NotFound, "正文版本不存在")
+)
+
+type IArticleService interface {
+	ListRevisions(ctx context.Context, articleID int64) ([]repository.ArticleRevision, error)
+	DiffRevisions(ctx context.Context, articleID, from, to int64) (string, error)
+	ListComments(ctx context.Context, articleID int64) ([]repository.Comment, error)
+}
+
+type ArticleService struct {
+	articles  repository.ArticleRepository
+	revisions repository.ArticleRevisionRepository
+	comments  repository.CommentRepository
+}
+
+func NewArticleService(articles repository.ArticleRepository, revisions repository.ArticleRevisionRepository, comments repository.CommentRepository) IArticleService {
+	return &ArticleService{
+		articles:  articles,
+		revisions: revisions,
+		comments:  comments,
+	}
+}
+
+// findArticle 查询文章，不存在时返回 ErrArticleNotFound
+func (s *ArticleService) findArticle(ctx context.Context, id int64) (*repository.Article, error) {
+	article, err := s.articles.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrArticleNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询文章失败: %w", err)
+	}
+	return article, nil
+}
+
+// ListRevisions 按时间顺序返回文章的正文版本，不含正文
+func (s *ArticleService) ListRevisions(ctx context.Context, articleID int64) ([]repository.ArticleRevision, error) {
+	if _, err := s.findArticle(ctx, articleID); err != nil {
+		return nil, err
+	}
+	revisions, err := s.revisions.FindByArticle(ctx, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("查询正文版本失败: %w", err)
+	}
+	return revisions, nil
+}
+
+// DiffRevisions 返回两个版本正文纯文本的 unified diff。
+// to 为 0 时使用最新版本，from 为 0 时使用 to 的上一个版本
+func (s *ArticleService) DiffRevisions(ctx context.Context, articleID, from, to int64) (string, error) {
+	revisions, err := s.ListRevisions(ctx, articleID)
+	if err != nil {
+		return "", err
+	}
+	if len(revisions) == 0 {
+		return "", ErrRevisionNotFound
+	}
+
+	if to == 0 {
+		to = revisions[len(revisions)-1].ID
+	}
+	// to 是第一个版本时 from 保持为 0，与空正文比较
+	if from == 0 {
+		for i, revision := range revisions {
+			if revision.ID == to && i > 0 {
+				from = revisions[i-1].ID
+			}
+		}
+	}
+
+	newer, err := s.findRevision(ctx, articleID, to)
+	if err != nil {
+		return "", err
+	}
+	older := &repository.ArticleRevision{}
+	if from != 0 {
+		if older, err = s.findRevision(ctx, articleID, from); err != nil {
+			return "", err
+		}
+	}
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(older.Text),
+		B:        difflib.SplitLines(newer.Text),
+		FromFile: revisionName(older),
+		FromDate: revisionDate(older),
+		ToFile:   revisionName(newer),
+		ToDate:   revisionDate(newer),
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("生成差异失败: %w", err)
+	}
+	return diff, nil
+}
+
+// ListComments 按评论时间返回文章的全部评论和回复
+func (s *ArticleService) ListComments(ctx context.Context, articleID int64) ([]repository.Comment, error) {
+	if _, err := s.findArticle(ctx, articleID); err != nil {
+		return nil, err
+	}
+	comments, err := s.comments.FindByArticle(ctx, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("查询评论失败: %w", err)
+	}
+	return comments, nil
+}
+
+// findRevision 查询文章的指定版本，不存在时返回 ErrRevisionNotFound
+func (s *ArticleService) findRevision(ctx context.Context, articleID, id int64) (*repository.ArticleRevision, error) {
+	revision, err := s.revisions.FindByID(ctx, articleID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRevisionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询正文版本失败: %w", err)
+	}
+	return revision, nil
+}
+
+func revisionName(r *repository.ArticleRevision) string {
+	if r.ID == 0 {
+		return "/dev/null"
+	}
+	return fmt.Sprintf("revision-%d", r.ID)
+}
+
+func revisionDate(r *repository.ArticleRevision) string {
+	if r.CreatedAt.IsZero() {
+		return ""
+	}
+	return r.CreatedAt.Format("2006-01-02 15:04:05")
+}
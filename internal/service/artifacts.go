@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const defaultArtifactsDir = "./data/artifacts"
+
+var ErrArtifactNotFound = errcode.New(errcode.NotFound, "文件不存在")
+
+// Artifact 任务失败现场文件
+type Artifact struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// artifactsDir 任务的现场文件目录
+func (s *CrawlerService) artifactsDir(runID string) string {
+	dir := s.config.Artifacts.Dir
+	if dir == "" {
+		dir = defaultArtifactsDir
+	}
+	return filepath.Join(dir, runID)
+}
+
+// captureArtifacts 保存页面全屏截图和 HTML，失败只记录日志不影响任务
+func (s *CrawlerService) captureArtifacts(ctx context.Context, page playwright.Page, runID, reason string) {
+	log := logger.FromContext(ctx)
+
+	dir := s.artifactsDir(runID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		log.Warn("创建现场目录失败", "dir", dir, "error", err)
+		return
+	}
+
+	prefix := filepath.Join(dir, time.Now().Format("150405.000")+"-"+reason)
+
+	if _, err := page.Screenshot(playwright.PageScreenshotOptions{
+		Path:     playwright.String(prefix + ".png"),
+		FullPage: playwright.Bool(true),
+	}); err != nil {
+		log.Warn("保存页面截图失败", "reason", reason, "error", err)
+	}
+
+	html, err := page.Content()
+	if err != nil {
+		log.Warn("获取页面HTML失败", "reason", reason, "error", err)
+	} else if err := os.WriteFile(prefix+".html", []byte(html), 0644); err != nil {
+		log.Warn("保存页面HTML失败", "reason", reason, "error", err)
+	}
+
+	log.Info("已保存失败现场",
+		"reason", reason,
+		"url", page.URL(),
+		"dir", dir,
+	)
+}
+
+// startTracing 按配置开始录制 Playwright trace，返回的函数在任务结束时调用，失败时保存 trace.zip
+func (s *CrawlerService) startTracing(ctx context.Context, browserCtx playwright.BrowserContext, runID string) func(failed bool) {
+	if !s.config.Artifacts.Trace {
+		return func(bool) {}
+	}
+
+	log := logger.FromContext(ctx)
+	if err := browserCtx.Tracing().Start(playwright.TracingStartOptions{
+		Screenshots: playwright.Bool(true),
+		Snapshots:   playwright.Bool(true),
+	}); err != nil {
+		log.Warn("开始录制 trace 失败", "error", err)
+		return func(bool) {}
+	}
+
+	return func(failed bool) {
+		if !failed {
+			if err := browserCtx.Tracing().Stop(); err != nil {
+				log.Warn("停止录制 trace 失败", "error", err)
+			}
+			return
+		}
+
+		dir := s.artifactsDir(runID)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			log.Warn("创建现场目录失败", "dir", dir, "error", err)
+			return
+		}
+		if err := browserCtx.Tracing().Stop(filepath.Join(dir, "trace.zip")); err != nil {
+			log.Warn("保存 trace 失败", "error", err)
+		}
+	}
+}
+
+// ListArtifacts 列出任务的现场文件
+func (s *CrawlerService) ListArtifacts(ctx context.Context, id string) ([]Artifact, error) {
+	if _, err := s.GetRun(ctx, id); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(s.artifactsDir(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return []Artifact{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取现场目录失败: %w", err)
+	}
+
+	artifacts := make([]Artifact, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		artifacts = append(artifacts, Artifact{
+			Name:    entry.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+	sort.Slice(artifacts, func(i, j int) bool { return artifacts[i].Name < artifacts[j].Name })
+	return artifacts, nil
+}
+
+// ArtifactPath 返回任务现场文件的路径，文件名不能包含目录
+func (s *CrawlerService) ArtifactPath(ctx context.Context, id, name string) (string, error) {
+	if _, err := s.GetRun(ctx, id); err != nil {
+		return "", err
+	}
+	if name == "" || filepath.Base(name) != name || name == "." || name == ".." {
+		return "", ErrArtifactNotFound
+	}
+
+	path := filepath.Join(s.artifactsDir(id), name)
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return "", ErrArtifactNotFound
+	}
+	return path, nil
+}
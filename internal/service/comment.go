@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"crawler/internal/progress"
+	"crawler/internal/repository"
+	"crawler/internal/scraper"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/retry"
+	"errors"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// crawlComments 抓取已保存评论数少于页面评论数的文章的评论。
+// 全量任务翻完全部一级评论并重新抓取全部回复；增量任务翻到已保存的最新一级评论后停止，
+// 只抓取新评论和回复数有变化的评论的回复。
+// 抓取过的文章记录抓取时间，评论数没有变化时在 comments.retryInterval 内不再抓取。
+// 单篇失败只记录警告，被拦截或登录失效时停止抓取
+func (s *CrawlerService) crawlComments(ctx context.Context, page playwright.Page, run *repository.CrawlRun, data []scraper.ArticleCard) error {
+	if !s.config.Comments.Enabled || len(data) == 0 {
+		return nil
+	}
+	log := logger.FromContext(ctx)
+
+	links := make([]string, len(data))
+	for i, article := range data {
+		links[i] = article.Link
+	}
+	targets, err := s.comments.Targets(ctx, links, s.config.Comments.Limit(), s.config.Comments.RetryIntervalOrDefault())
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Warn("查询需要抓取评论的文章失败，跳过评论抓取", "error", err)
+		return nil
+	}
+
+	log.Info("开始抓取评论", "articles", len(targets))
+	for i, target := range targets {
+		if i > 0 {
+			if err := scraper.Pause(ctx, s.config.Pacing); err != nil {
+				return err
+			}
+		}
+
+		opts := scraper.CommentOptions{
+			Pacing: s.config.Pacing,
+			Retry:  s.config.Retry,
+		}
+		if run.Mode == repository.CrawlModeIncremental && target.Stored > 0 {
+			if opts.KnownReplies, err = s.comments.ReplyCounts(ctx, target.ArticleID); err != nil {
+				log.Warn("查询已保存的回复数失败，抓取全部回复", "link", target.Link, "error", err)
+			}
+			if opts.Since, err = s.comments.LatestRootCommentAt(ctx, target.ArticleID); err != nil {
+				log.Warn("查询已保存的最新评论时间失败，翻完全部评论", "link", target.Link, "error", err)
+			}
+		}
+		comments, err := scraper.FetchComments(ctx, page.Request(), target.Link, opts)
+		if err != nil {
+			switch errcode.Of(err) {
+			case errcode.Blocked, errcode.NotLoggedIn:
+				log.Warn("抓取评论被拦截，停止抓取", "link", target.Link, "error", err)
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Warn("抓取评论失败，跳过", "link", target.Link, "error", err)
+			s.markCommentsChecked(ctx, target)
+			continue
+		}
+
+		err = retry.Do(ctx, s.config.Retry, retry.OpDBComments, func(int) error {
+			return s.comments.UpsertComments(ctx, target.ArticleID, comments, run.ID)
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			log.Warn("保存评论失败", "link", target.Link, "error", err)
+			continue
+		}
+
+		s.markCommentsChecked(ctx, target)
+		run.CommentCount += len(comments)
+		progress.Report(ctx, progress.EventCommentsCrawled, map[string]interface{}{
+			"link":  target.Link,
+			"count": len(comments),
+		})
+	}
+
+	log.Info("评论抓取完成", "comments", run.CommentCount)
+	return nil
+}
+
+// markCommentsChecked 记录文章已抓取过评论，失败只记录警告
+func (s *CrawlerService) markCommentsChecked(ctx context.Context, target repository.CommentTarget) {
+	if err := s.comments.MarkChecked(ctx, target.ArticleID); err != nil {
+		logger.FromContext(ctx).Warn("记录评论抓取时间失败", "link", target.Link, "error", err)
+	}
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"crawler/internal/progress"
+	"crawler/internal/repository"
+	"crawler/internal/scraper"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/retry"
+	"errors"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// crawlContents 逐篇打开本次出现的文章抓取正文，正文变化时保存新版本。
+// 单篇失败和查询待抓取文章失败只记录警告；被拦截或登录失效时停止抓取，不影响任务结果，只有任务被取消时返回错误
+func (s *CrawlerService) crawlContents(ctx context.Context, page playwright.Page, run *repository.CrawlRun, data []scraper.ArticleCard) error {
+	if !s.config.Content.Enabled || len(data) == 0 {
+		return nil
+	}
+	log := logger.FromContext(ctx)
+
+	links := make([]string, len(data))
+	for i, article := range data {
+		links[i] = article.Link
+	}
+	targets, err := s.revisions.StaleLinks(ctx, links, s.config.Content.Limit())
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		log.Warn("查询待抓取正文的文章失败，跳过正文抓取", "error", err)
+		return nil
+	}
+
+	log.Info("开始抓取文章正文", "count", len(targets))
+	crawled := 0
+	for i, link := range targets {
+		if i > 0 {
+			if err := scraper.Pause(ctx, s.config.Pacing); err != nil {
+				return err
+			}
+		}
+
+		content, err := scraper.ExtractContent(ctx, page, link, s.config.Retry)
+		if err != nil {
+			switch errcode.Of(err) {
+			case errcode.Blocked, errcode.NotLoggedIn:
+				log.Warn("抓取正文被拦截，停止抓取", "link", link, "error", err)
+				s.captureArtifacts(ctx, page, run.ID, "content_"+errcode.Of(err).String())
+				return nil
+			}
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			log.Warn("抓取正文失败，跳过", "link", link, "error", err)
+			continue
+		}
+
+		var created bool
+		err = retry.Do(ctx, s.config.Retry, retry.OpDBRevision, func(int) (err error) {
+			created, err = s.revisions.SaveRevision(ctx, content, run.ID)
+			return err
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			log.Warn("保存正文版本失败", "link", link, "error", err)
+			continue
+		}
+
+		crawled++
+		if created {
+			run.RevisionCount++
+			log.Info("发现新的正文版本", "link", link, "title", content.Title)
+		}
+		progress.Report(ctx, progress.EventContentCrawled, map[string]interface{}{
+			"link":    link,
+			"changed": created,
+		})
+	}
+
+	log.Info("文章正文抓取完成",
+		"crawled", crawled,
+		"revisions", run.RevisionCount,
+	)
+	return nil
+}
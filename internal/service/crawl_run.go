@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"crawler/internal/notify"
+	"crawler/internal/progress"
+	"crawler/internal/proxy"
+	"crawler/internal/repository"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/retry"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrRunNotFound = errcode.New(errcode.NotFound, "爬取任务不存在")
+
+// activeRun 进行中任务的日志、进度事件、使用的代理和重试计数
+type activeRun struct {
+	logs    *logger.Capture
+	events  *progress.Stream
+	proxy   *proxy.Proxy
+	retries *retry.Counter
+}
+
+// prepareRun 确定爬取模式，检查爬取间隔、分配代理并创建任务记录，同时登记任务日志和进度事件流。
+// 分配代理或创建任务记录失败时撤销本次爬取时间的记录
+func (s *CrawlerService) prepareRun(ctx context.Context, opts CrawlOptions) (_ *repository.CrawlRun, _ *activeRun, err error) {
+	account := s.account()
+	mode, err := s.resolveMode(ctx, account, opts.Mode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	release, err := s.acquireCrawlSlot(account)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() {
+		if err != nil {
+			release()
+		}
+	}()
+
+	// 同一账号固定使用同一个代理
+	px, err := s.proxies.Pick(account)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.Source == "" {
+		opts.Source = SourceAPI
+	}
+
+	run := &repository.CrawlRun{
+		ID:        uuid.New().String(),
+		Account:   account,
+		Source:    opts.Source,
+		Mode:      mode,
+		Status:    repository.CrawlRunRunning,
+		StartedAt: time.Now(),
+	}
+	if px != nil {
+		run.Proxy = px.String()
+	}
+	if err := s.runs.Create(ctx, run); err != nil {
+		return nil, nil, fmt.Errorf("创建爬取任务记录失败: %w", err)
+	}
+
+	active := &activeRun{
+		logs:    logger.NewCapture(),
+		events:  progress.NewStream(run.ID),
+		proxy:   px,
+		retries: retry.NewCounter(),
+	}
+	s.mu.Lock()
+	s.activeRuns[run.ID] = active
+	s.mu.Unlock()
+
+	return run, active, nil
+}
+
+// resolveMode 确定本次任务的爬取模式。auto 模式下距离上次成功的全量爬取超过 fullRefreshInterval 时全量，否则增量
+func (s *CrawlerService) resolveMode(ctx context.Context, account, requested string) (string, error) {
+	mode := requested
+	if mode == "" {
+		mode = s.config.Crawl.ModeName()
+	}
+	switch mode {
+	case config.CrawlModeFull:
+		return repository.CrawlModeFull, nil
+	case config.CrawlModeIncremental:
+		return repository.CrawlModeIncremental, nil
+	case config.CrawlModeAuto:
+	default:
+		return "", errcode.New(errcode.InvalidRequest, "不支持的爬取模式: "+mode)
+	}
+
+	last, err := s.runs.FindLatestSuccess(ctx, account, repository.CrawlModeFull)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repository.CrawlModeFull, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("查询上次全量爬取失败: %w", err)
+	}
+	if time.Since(last.StartedAt) >= s.config.Crawl.RefreshInterval() {
+		return repository.CrawlModeFull, nil
+	}
+	return repository.CrawlModeIncremental, nil
+}
+
+// finishRun 保存任务结果和日志，保存完成后才移除进行中的日志，保证查询方总能读到完整日志
+func (s *CrawlerService) finishRun(ctx context.Context, run *repository.CrawlRun, active *activeRun, runErr error) {
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	if runErr != nil {
+		run.Status = repository.CrawlRunFailed
+		run.Error = runErr.Error()
+		run.FailReason = errcode.Of(runErr).String()
+	} else {
+		run.Status = repository.CrawlRunSuccess
+	}
+	run.Retries = active.retries.Total()
+	if run.Retries > 0 {
+		logger.FromContext(ctx).Info("任务重试统计",
+			"retries", run.Retries,
+			"by_operation", active.retries.ByOperation(),
+		)
+	}
+	run.Logs = active.logs.String()
+
+	if err := s.runs.Save(context.WithoutCancel(ctx), run); err != nil {
+		logger.FromContext(ctx).Error("保存爬取任务记录失败",
+			"error", err,
+		)
+	}
+
+	active.events.Publish(RunEventType(run), RunEventData(run))
+	active.logs.Close()
+	active.events.Close()
+	s.mu.Lock()
+	delete(s.activeRuns, run.ID)
+	s.mu.Unlock()
+
+	s.notifyFailure(ctx, run)
+}
+
+// notifyFailure 任务因配置的原因（默认为被拦截和登录失效）失败时发送通知，发送失败只记录日志
+func (s *CrawlerService) notifyFailure(ctx context.Context, run *repository.CrawlRun) {
+	if run.Status != repository.CrawlRunFailed || !s.notifier.ShouldNotify(run.FailReason) {
+		return
+	}
+	err := s.notifier.Notify(context.WithoutCancel(ctx), notify.Notification{
+		Event:      notify.EventCrawlFailed,
+		JobID:      run.ID,
+		Account:    run.Account,
+		Source:     run.Source,
+		Mode:       run.Mode,
+		Reason:     run.FailReason,
+		Error:      run.Error,
+		FinishedAt: *run.FinishedAt,
+	})
+	if err != nil {
+		logger.FromContext(ctx).Warn("发送任务失败通知失败",
+			"reason", run.FailReason,
+			"error", err,
+		)
+	}
+}
+
+// GetRun 查询任务记录
+func (s *CrawlerService) GetRun(ctx context.Context, id string) (*repository.CrawlRun, error) {
+	run, err := s.runs.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRunNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("查询爬取任务失败: %w", err)
+	}
+	return run, nil
+}
+
+// RunLogs 返回进行中任务的日志收集器，任务已结束时返回 false
+func (s *CrawlerService) RunLogs(id string) (*logger.Capture, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	active, ok := s.activeRuns[id]
+	if !ok {
+		return nil, false
+	}
+	return active.logs, true
+}
+
+// RunEvents 返回进行中任务的进度事件流，任务已结束时返回 false
+func (s *CrawlerService) RunEvents(id string) (*progress.Stream, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	active, ok := s.activeRuns[id]
+	if !ok {
+		return nil, false
+	}
+	return active.events, true
+}
+
+// RunEventType 任务结束时的事件类型，服务重启导致中断的任务按失败处理
+func RunEventType(run *repository.CrawlRun) string {
+	if run.Status == repository.CrawlRunSuccess {
+		return progress.EventFinished
+	}
+	return progress.EventFailed
+}
+
+// RunEventData 任务结束事件的数据
+func RunEventData(run *repository.CrawlRun) map[string]interface{} {
+	data := map[string]interface{}{
+		"status":        run.Status,
+		"article_count": run.ArticleCount,
+		"retries":       run.Retries,
+		"deleted":       run.DeletedCount,
+		"restored":      run.RestoredCount,
+		"revisions":     run.RevisionCount,
+		"comments":      run.CommentCount,
+	}
+	if run.Error != "" {
+		data["error"] = run.Error
+	}
+	if run.FailReason != "" {
+		data["reason"] = run.FailReason
+	}
+	return data
+}
@@ -1,37 +1,147 @@
 package service
 
 import (
+	"context"
+	"crawler/internal/browser"
+	"crawler/internal/notify"
+	"crawler/internal/progress"
+	"crawler/internal/proxy"
 	"crawler/internal/repository"
 	"crawler/internal/scraper"
 	"crawler/pkg/config"
 	"crawler/pkg/cookies"
+	"crawler/pkg/errcode"
 	"crawler/pkg/logger"
+	"crawler/pkg/metrics"
+	"crawler/pkg/retry"
+	"crawler/pkg/tracing"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/playwright-community/playwright-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
 )
 
 type ICrawlerService interface {
 	CheckPrerequisites() error
-	ExecuteCrawl() error
-	Initialize() error
-	Cleanup()
+	ExecuteCrawl(ctx context.Context, opts CrawlOptions) (*repository.CrawlRun, error)
+	StartCrawl(ctx context.Context, opts CrawlOptions) (*repository.CrawlRun, error)
+	GetRun(ctx context.Context, id string) (*repository.CrawlRun, error)
+	RunLogs(id string) (*logger.Capture, bool)
+	RunEvents(id string) (*progress.Stream, bool)
+	ListArtifacts(ctx context.Context, id string) ([]Artifact, error)
+	ArtifactPath(ctx context.Context, id, name string) (string, error)
 }
 
+// 爬取任务触发来源
+const (
+	SourceAPI = "api"
+)
+
+// CrawlOptions 单次爬取任务的参数
+type CrawlOptions struct {
+	Source string // 触发来源
+	Debug  bool   // 为本次任务临时开启调试日志
+	Mode   string // 爬取模式: auto/full/incremental，为空时使用配置
+}
+
+// ThrottledError 同一账号爬取过于频繁
+type ThrottledError struct {
+	Account string
+	Wait    time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("账号 %s 爬取过于频繁，请在 %s 后重试", e.Account, e.Wait.Round(time.Second))
+}
+
+func (e *ThrottledError) ErrorCode() errcode.Code { return errcode.Throttled }
+
+func (e *ThrottledError) RetryAfter() time.Duration { return e.Wait }
+
 type CrawlerService struct {
 	config     *config.Config
-	pw         *playwright.Playwright
-	browser    playwright.Browser
+	browsers   browser.Manager
+	proxies    proxy.Pool
+	notifier   notify.Notifier
 	repository repository.ArticleRepository
+	runs       repository.CrawlRunRepository
+	revisions  repository.ArticleRevisionRepository
+	comments   repository.CommentRepository
+	accounts   repository.AccountMetricsRepository
+	analytics  repository.AnalyticsRepository
+
+	mu         sync.Mutex
+	lastCrawl  map[string]time.Time  // 账号 -> 最近一次开始爬取的时间
+	activeRuns map[string]*activeRun // 进行中的任务
 }
 
-func NewCrawlerService(cfg *config.Config, repo repository.ArticleRepository) ICrawlerService {
+func NewCrawlerService(cfg *config.Config, browsers browser.Manager, proxies proxy.Pool, notifier notify.Notifier, repo repository.ArticleRepository, runs repository.CrawlRunRepository, revisions repository.ArticleRevisionRepository, comments repository.CommentRepository, accounts repository.AccountMetricsRepository, analytics repository.AnalyticsRepository) ICrawlerService {
 	return &CrawlerService{
 		config:     cfg,
+		browsers:   browsers,
+		proxies:    proxies,
+		notifier:   notifier,
 		repository: repo,
+		runs:       runs,
+		revisions:  revisions,
+		comments:   comments,
+		accounts:   accounts,
+		analytics:  analytics,
+		lastCrawl:  make(map[string]time.Time),
+		activeRuns: make(map[string]*activeRun),
+	}
+}
+
+// account 当前爬取使用的账号
+func (s *CrawlerService) account() string {
+	return accountName(s.config)
+}
+
+// accountName 配置的知乎账号，未配置用户名时为 default
+func accountName(cfg *config.Config) string {
+	if cfg.App.Username != "" {
+		return cfg.App.Username
+	}
+	return "default"
+}
+
+// acquireCrawlSlot 检查账号的最小爬取间隔，通过后记录本次爬取时间。
+// 返回的 release 用于任务没有创建成功时撤销本次记录，避免没有执行的任务也占用爬取间隔
+func (s *CrawlerService) acquireCrawlSlot(account string) (release func(), err error) {
+	minInterval := s.config.RateLimit.CrawlMinInterval
+	if minInterval <= 0 {
+		return func() {}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	last, ok := s.lastCrawl[account]
+	if ok {
+		if wait := minInterval - now.Sub(last); wait > 0 {
+			return nil, &ThrottledError{Account: account, Wait: wait}
+		}
 	}
+	s.lastCrawl[account] = now
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		// 期间已有新的任务记录了爬取时间时不覆盖
+		if !s.lastCrawl[account].Equal(now) {
+			return
+		}
+		if ok {
+			s.lastCrawl[account] = last
+		} else {
+			delete(s.lastCrawl, account)
+		}
+	}, nil
 }
 
 // CheckPrerequisites 检查爬虫执行的前置条件
@@ -39,123 +149,264 @@ func (s *CrawlerService) CheckPrerequisites() error {
 	// 检查 cookies 文件
 	cookiesPath := s.config.App.CookiesFilePath
 	if _, err := os.Stat(cookiesPath); os.IsNotExist(err) {
-		return fmt.Errorf("cookies文件不存在: %s", cookiesPath)
+		return errcode.New(errcode.PrerequisiteFailed, "cookies文件不存在: "+cookiesPath)
 	}
 	return nil
 }
 
-// Initialize 初始化浏览器
-func (s *CrawlerService) Initialize() error {
-	start := time.Now()
-	logger.Info("初始化 Playwright",
-		"step", "start",
-		"timestamp", start.Format(time.RFC3339),
-	)
-
-	pw, err := playwright.Run()
+// ExecuteCrawl 同步执行爬虫任务，返回任务记录
+func (s *CrawlerService) ExecuteCrawl(ctx context.Context, opts CrawlOptions) (*repository.CrawlRun, error) {
+	run, active, err := s.prepareRun(ctx, opts)
 	if err != nil {
-		logger.Error("Playwright 安装失败",
-			"error", err,
-			"duration", time.Since(start).String(),
-		)
-		return fmt.Errorf("playwright installation failed: %w", err)
-	}
-
-	browserOpts := playwright.BrowserTypeLaunchOptions{
-		Headless: playwright.Bool(false), // 使用浏览器模式
+		return nil, err
 	}
+	return run, s.executeRun(ctx, run, active, opts)
+}
 
-	browser, err := pw.Chromium.Launch(browserOpts)
+// StartCrawl 创建任务记录后在后台执行爬虫任务，立即返回任务记录
+func (s *CrawlerService) StartCrawl(ctx context.Context, opts CrawlOptions) (*repository.CrawlRun, error) {
+	run, active, err := s.prepareRun(ctx, opts)
 	if err != nil {
-		pw.Stop()
-		logger.Error("浏览器启动失败",
-			"error", err,
-			"options", browserOpts,
-			"duration", time.Since(start).String(),
-		)
-		return fmt.Errorf("browser launch failed: %w", err)
+		return nil, err
 	}
 
-	s.pw = pw
-	s.browser = browser
+	// 返回副本，避免与后台任务并发读写
+	snapshot := *run
 
-	logger.Info("Playwright 初始化完成",
-		"duration", time.Since(start).String(),
-	)
+	// 后台任务不随请求结束而取消，但保留上下文中的日志和链路信息
+	bgCtx := context.WithoutCancel(ctx)
+	go s.executeRun(bgCtx, run, active, opts)
 
-	return nil
+	return &snapshot, nil
 }
 
-// Cleanup 清理资源
-func (s *CrawlerService) Cleanup() {
-	if s.browser != nil {
-		s.browser.Close()
-	}
-	if s.pw != nil {
-		s.pw.Stop()
+// executeRun 执行任务并将结果和日志写回任务记录
+func (s *CrawlerService) executeRun(ctx context.Context, run *repository.CrawlRun, active *activeRun, opts CrawlOptions) (err error) {
+	ctx, span := tracing.Start(ctx, "crawler.crawl",
+		attribute.String("crawler.job_id", run.ID),
+		attribute.String("crawler.account", run.Account),
+		attribute.String("crawler.source", run.Source),
+		attribute.String("crawler.mode", run.Mode),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	// 后续各层通过上下文获取的日志都带上任务字段，并同时写入任务日志
+	jobLogger := logger.FromContext(ctx).WithCapture(active.logs).WithFields(map[string]interface{}{
+		"job_id":  run.ID,
+		"account": run.Account,
+		"source":  run.Source,
+		"mode":    run.Mode,
+	}).WithModule(logger.ModuleService)
+	if opts.Debug {
+		jobLogger = jobLogger.WithLevel(zapcore.DebugLevel)
 	}
-}
+	ctx = logger.WithContext(ctx, jobLogger)
+	ctx = progress.WithStream(ctx, active.events)
+	ctx = retry.WithCounter(ctx, active.retries)
+	log := jobLogger
 
-// ExecuteCrawl 执行爬虫任务
-func (s *CrawlerService) ExecuteCrawl() error {
 	start := time.Now()
-	logger.Info("开始执行爬虫任务",
+	log.Info("开始执行爬虫任务",
 		"timestamp", start.Format(time.RFC3339),
 	)
 
-	// 初始化浏览器
-	if err := s.Initialize(); err != nil {
+	defer func() {
+		outcome := metrics.Outcome(err)
+		metrics.CrawlRunsTotal.WithLabelValues(outcome).Inc()
+		metrics.CrawlDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+
+		if err != nil {
+			log.Error("爬虫任务失败",
+				"error", err,
+				"duration", time.Since(start).String(),
+			)
+		}
+		s.finishRun(ctx, run, active, err)
+	}()
+
+	progress.Report(ctx, progress.EventStarted, map[string]interface{}{
+		"account": run.Account,
+		"source":  run.Source,
+		"mode":    run.Mode,
+	})
+
+	articleCount, err := s.crawl(ctx, run, active.proxy)
+	if err != nil {
 		return err
 	}
-	defer s.Cleanup()
+	run.ArticleCount = articleCount
+	span.SetAttributes(attribute.Int("crawler.articles", articleCount))
+
+	log.Info("数据提取完成",
+		"articleCount", articleCount,
+		"duration", time.Since(start).String(),
+	)
 
-	// 创建新的上下文
-	context, err := s.browser.NewContext()
+	return nil
+}
+
+// crawl 启动浏览器抓取文章列表并保存，返回文章数
+func (s *CrawlerService) crawl(ctx context.Context, run *repository.CrawlRun, px *proxy.Proxy) (_ int, err error) {
+	log := logger.FromContext(ctx)
+
+	// 从浏览器管理器获取独立的上下文，超过并发上限时等待
+	session, err := s.browsers.Acquire(ctx, px)
 	if err != nil {
-		return fmt.Errorf("failed to create browser context: %w", err)
+		return 0, err
 	}
-	defer context.Close()
+	defer session.Close()
+	browserCtx := session.Context
+
+	stopTracing := s.startTracing(ctx, browserCtx, run.ID)
+	defer func() { stopTracing(err != nil) }()
 
 	// 加载 cookies
-	if err := cookies.LoadCookies(context, s.config.App.CookiesFilePath); err != nil {
-		logger.Warn("加载 Cookies 失败，将使用无登录模式",
+	if err := cookies.LoadCookies(ctx, browserCtx, s.config.App.CookiesFilePath); err != nil {
+		log.Warn("加载 Cookies 失败，将使用无登录模式",
 			"error", err,
 			"cookiesPath", s.config.App.CookiesFilePath,
 		)
 	}
 
 	// 创建新页面
-	page, err := context.NewPage()
+	page, err := browserCtx.NewPage()
 	if err != nil {
-		return fmt.Errorf("failed to create new page: %w", err)
+		return 0, fmt.Errorf("failed to create new page: %w", err)
 	}
 	defer page.Close()
 
 	// 访问目标页面
 	targetURL := "https://www.zhihu.com/creator/manage/creation/article"
-	logger.Info("开始访问目标页面",
-		"url", targetURL,
-	)
+	if err := s.navigate(ctx, page, targetURL); err != nil {
+		s.captureArtifacts(ctx, page, run.ID, "navigation_failed")
+		return 0, err
+	}
 
-	if _, err := page.Goto(targetURL); err != nil {
-		return fmt.Errorf("failed to navigate to %s: %w", targetURL, err)
+	// 检查是否被安全验证拦截或登录失效，避免继续等待不会出现的列表
+	if err := scraper.DetectBlock(ctx, page); err != nil {
+		reason := errcode.Of(err).String()
+		log.Warn("页面被拦截，终止任务",
+			"reason", reason,
+			"url", page.URL(),
+			"error", err,
+		)
+		s.captureArtifacts(ctx, page, run.ID, reason)
+		return 0, err
 	}
+	progress.Report(ctx, progress.EventPageLoaded, map[string]interface{}{
+		"url": targetURL,
+	})
 
 	// 提取数据
-	data, err := scraper.ExtractData(page)
+	extractOpts := scraper.Options{
+		Pacing: s.config.Pacing,
+		Retry:  s.config.Retry,
+		OnFailure: func(ctx context.Context, reason string) {
+			s.captureArtifacts(ctx, page, run.ID, reason)
+		},
+	}
+	if run.Mode == repository.CrawlModeIncremental {
+		extractOpts.StopAfterKnown = s.config.Crawl.KnownLimit()
+		extractOpts.KnownLinks = s.repository.ExistingLinks
+	}
+	result, err := scraper.ExtractData(ctx, page, extractOpts)
 	if err != nil {
-		return fmt.Errorf("failed to extract data: %w", err)
+		return 0, fmt.Errorf("failed to extract data: %w", err)
 	}
+	data := result.Articles
+	metrics.ArticlesExtracted.Observe(float64(len(data)))
 
-	// 保存到数据库
-	if err := s.repository.UpsertArticles(data); err != nil {
-		return fmt.Errorf("failed to save articles: %w", err)
+	// 保存到数据库，数据库暂时不可用时重试
+	err = retry.Do(ctx, s.config.Retry, retry.OpDBUpsert, func(int) error {
+		return s.repository.UpsertArticles(ctx, data)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to save articles: %w", err)
 	}
+	progress.Report(ctx, progress.EventSaved, map[string]interface{}{
+		"count": len(data),
+	})
 
-	logger.Info("数据提取完成",
-		"articleCount", len(data),
-		"duration", time.Since(start).String(),
+	// 全量爬取完整时才能判断哪些文章已被删除
+	complete := run.Mode == repository.CrawlModeFull && result.Complete
+	if err := s.syncArticleStatus(ctx, run, data, complete); err != nil {
+		return 0, err
+	}
+
+	if err := s.snapshotStats(ctx, run, complete); err != nil {
+		return 0, fmt.Errorf("failed to snapshot article stats: %w", err)
+	}
+
+	if err := s.crawlAccountMetrics(ctx, page, run); err != nil {
+		return 0, fmt.Errorf("failed to crawl account metrics: %w", err)
+	}
+	if err := s.crawlContents(ctx, page, run, data); err != nil {
+		return 0, fmt.Errorf("failed to crawl article contents: %w", err)
+	}
+	if err := s.crawlComments(ctx, page, run, data); err != nil {
+		return 0, fmt.Errorf("failed to crawl comments: %w", err)
+	}
+
+	return len(data), nil
+}
+
+// navigate 访问目标页面
+func (s *CrawlerService) navigate(ctx context.Context, page playwright.Page, targetURL string) (err error) {
+	ctx, span := tracing.Start(ctx, "page.goto", attribute.String("url.full", targetURL))
+	defer func() { tracing.End(span, err) }()
+
+	logger.FromContext(ctx).Info("开始访问目标页面",
+		"url", targetURL,
 	)
 
+	return retry.Do(ctx, s.config.Retry, retry.OpNavigate, func(attempt int) error {
+		span.SetAttributes(attribute.Int("crawler.attempts", attempt))
+		resp, err := page.Goto(targetURL)
+		if err != nil {
+			return scraper.WrapNavigationError(targetURL, err)
+		}
+		if resp != nil {
+			span.SetAttributes(attribute.Int("http.response.status_code", resp.Status()))
+		}
+		return nil
+	})
+}
+
+// syncArticleStatus 恢复重新出现的文章，完整爬取时将未出现的文章标记为删除，并记录到任务
+func (s *CrawlerService) syncArticleStatus(ctx context.Context, run *repository.CrawlRun, data []scraper.ArticleCard, complete bool) error {
+	log := logger.FromContext(ctx)
+
+	links := make([]string, len(data))
+	for i, article := range data {
+		links[i] = article.Link
+	}
+
+	var changes *repository.StatusChanges
+	err := retry.Do(ctx, s.config.Retry, retry.OpDBSyncStatus, func(int) (err error) {
+		changes, err = s.repository.SyncStatus(ctx, links, complete, s.config.Crawl.MissingRatio())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync article status: %w", err)
+	}
+
+	run.DeletedCount = len(changes.Deleted)
+	run.RestoredCount = len(changes.Restored)
+	if len(changes.Deleted) > 0 {
+		log.Warn("文章已从知乎删除或取消发布", "count", len(changes.Deleted), "links", changes.Deleted)
+	}
+	if len(changes.Restored) > 0 {
+		log.Info("文章重新出现，已恢复", "count", len(changes.Restored), "links", changes.Restored)
+	}
+	if changes.Skipped {
+		log.Warn("本次没有出现的文章过多，列表可能没有加载完整，跳过删除检测",
+			"missing", changes.Missing,
+			"collected", len(links),
+			"max_missing_ratio", s.config.Crawl.MissingRatio(),
+		)
+	}
+	if !complete {
+		log.Info("本次爬取不完整，跳过删除检测", "mode", run.Mode)
+	}
 	return nil
 }
@@ -0,0 +1,400 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"crawler/internal/export"
+	"crawler/internal/proxy"
+	"crawler/internal/repository"
+	"crawler/internal/scraper"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+var ErrNothingToExport = errcode.New(errcode.NotFound, "没有可导出的文章")
+
+// 图片保存的子目录
+const assetsDir = "assets"
+
+// ExportOptions Markdown 导出参数
+type ExportOptions struct {
+	IDs            []int64  // 要导出的文章ID，为空时导出全部
+	IncludeDeleted bool     // 是否包含已删除的文章
+	Tags           []string // 追加到配置默认标签之后的标签
+	SkipImages     bool     // 不下载图片，保留知乎图片地址
+	// Keep 为 true 时保存到配置的导出目录，否则在临时目录中生成，调用方使用完后需要调用 ExportResult.Cleanup
+	Keep bool
+}
+
+// ExportResult 导出结果
+type ExportResult struct {
+	Dir          string // 导出目录
+	ZipPath      string // 打包后的 zip 文件
+	Articles     int
+	Images       int
+	FailedImages int
+
+	tempDir string // 临时导出时包含导出目录和 zip 的临时目录
+}
+
+// Cleanup 删除临时导出的目录和 zip，保存到导出目录的结果不受影响
+func (r *ExportResult) Cleanup() error {
+	if r.tempDir == "" {
+		return nil
+	}
+	return os.RemoveAll(r.tempDir)
+}
+
+type IExportService interface {
+	ExportMarkdown(ctx context.Context, opts ExportOptions) (*ExportResult, error)
+}
+
+type ExportService struct {
+	config    *config.Config
+	proxies   proxy.Pool
+	articles  repository.ArticleRepository
+	revisions repository.ArticleRevisionRepository
+}
+
+func NewExportService(cfg *config.Config, proxies proxy.Pool, articles repository.ArticleRepository, revisions repository.ArticleRevisionRepository) IExportService {
+	return &ExportService{
+		config:    cfg,
+		proxies:   proxies,
+		articles:  articles,
+		revisions: revisions,
+	}
+}
+
+// frontMatter Markdown 文件开头的 YAML 元数据
+type frontMatter struct {
+	Title string       `yaml:"title"`
+	Date  string       `yaml:"date,omitempty"`
+	Link  string       `yaml:"link"`
+	Stats exportedStat `yaml:"stats"`
+	Tags  []string     `yaml:"tags"`
+}
+
+type exportedStat struct {
+	Reads     int `yaml:"reads"`
+	Upvotes   int `yaml:"upvotes"`
+	Comments  int `yaml:"comments"`
+	Bookmarks int `yaml:"bookmarks"`
+	Likes     int `yaml:"likes"`
+}
+
+// ExportMarkdown 将文章导出为带 YAML front matter 的 Markdown 文件，图片下载到 assets 目录，最后打包为 zip。
+// 正文使用最新的正文版本，没有抓取过正文的文章只导出摘要。导出失败时删除已生成的文件
+func (s *ExportService) ExportMarkdown(ctx context.Context, opts ExportOptions) (_ *ExportResult, err error) {
+	log := logger.FromContext(ctx).WithModule(logger.ModuleService)
+
+	articles, err := s.articles.FindForExport(ctx, opts.IDs, opts.IncludeDeleted)
+	if err != nil {
+		return nil, fmt.Errorf("查询文章失败: %w", err)
+	}
+	if len(articles) == 0 {
+		return nil, ErrNothingToExport
+	}
+
+	result := &ExportResult{Articles: len(articles)}
+	root := s.config.Export.OutputDir()
+	if !opts.Keep {
+		if result.tempDir, err = os.MkdirTemp("", "crawler-export-*"); err != nil {
+			return nil, fmt.Errorf("创建临时目录失败: %w", err)
+		}
+		root = result.tempDir
+	}
+	dir := filepath.Join(root, "markdown-"+time.Now().Format("20060102-150405.000"))
+	result.Dir, result.ZipPath = dir, dir+".zip"
+	defer func() {
+		if err == nil {
+			return
+		}
+		if result.tempDir != "" {
+			os.RemoveAll(result.tempDir)
+			return
+		}
+		os.RemoveAll(dir)
+		os.Remove(result.ZipPath)
+	}()
+	if err := os.MkdirAll(filepath.Join(dir, assetsDir), 0o755); err != nil {
+		return nil, fmt.Errorf("创建导出目录失败: %w", err)
+	}
+
+	images, err := s.newImageMirror(ctx, dir, opts.SkipImages)
+	if err != nil {
+		return nil, err
+	}
+	tags := append(append([]string{}, s.config.Export.Tags...), opts.Tags...)
+
+	log.Info("开始导出文章", "count", len(articles), "dir", dir)
+	used := make(map[string]bool, len(articles))
+	for _, article := range articles {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		name := export.ArticleFilename(article.Title, article.PublishedAt)
+		if used[name] {
+			name = fmt.Sprintf("%s-%d", name, article.ID)
+		}
+		used[name] = true
+
+		content, err := s.articleMarkdown(ctx, article, tags, images.localize)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(content), 0o644); err != nil {
+			return nil, fmt.Errorf("写入 %s 失败: %w", name, err)
+		}
+	}
+
+	if err := zipDir(dir, result.ZipPath); err != nil {
+		return nil, fmt.Errorf("打包导出文件失败: %w", err)
+	}
+
+	result.Images = len(images.files)
+	result.FailedImages = images.failed
+	log.Info("导出完成",
+		"articles", result.Articles,
+		"images", result.Images,
+		"failed_images", result.FailedImages,
+		"zip", result.ZipPath,
+	)
+	return result, nil
+}
+
+// articleMarkdown 生成单篇文章的 Markdown
+func (s *ExportService) articleMarkdown(ctx context.Context, article repository.Article, tags []string, image export.ImageFunc) (string, error) {
+	meta := frontMatter{
+		Title: article.Title,
+		Link:  scraper.ArticleURL(article.Link),
+		Stats: exportedStat{
+			Reads:     article.ViewCount,
+			Upvotes:   article.Upvote,
+			Comments:  article.Comments,
+			Bookmarks: article.Bookmarks,
+			Likes:     article.Likes,
+		},
+		Tags: tags,
+	}
+	if article.PublishedAt != nil {
+		meta.Date = article.PublishedAt.Format(time.DateTime)
+	}
+	header, err := yaml.Marshal(meta)
+	if err != nil {
+		return "", fmt.Errorf("生成 front matter 失败: %w", err)
+	}
+
+	body := article.Description
+	revision, err := s.revisions.FindLatest(ctx, article.ID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		logger.FromContext(ctx).Warn("文章没有抓取过正文，只导出摘要", "article_id", article.ID, "title", article.Title)
+	case err != nil:
+		return "", fmt.Errorf("查询正文失败: %w", err)
+	default:
+		if body, err = export.ToMarkdown(revision.Content, image); err != nil {
+			return "", err
+		}
+	}
+
+	return "---\n" + string(header) + "---\n\n" + body + "\n", nil
+}
+
+// imageMirror 将正文中的图片下载到导出目录，同一图片只下载一次
+type imageMirror struct {
+	ctx    context.Context
+	dir    string
+	client *http.Client
+	ua     string
+	limit  int64 // 单张图片的最大字节数
+	skip   bool
+	files  map[string]string // 图片地址 -> 相对路径
+	failed int
+}
+
+// newImageMirror 创建图片下载器，下载经过当前账号使用的代理
+func (s *ExportService) newImageMirror(ctx context.Context, dir string, skip bool) (*imageMirror, error) {
+	m := &imageMirror{
+		ctx:   ctx,
+		dir:   dir,
+		ua:    s.config.Browser.UserAgent,
+		limit: s.config.Export.MaxImageBytesOrDefault(),
+		skip:  skip,
+		files: make(map[string]string),
+	}
+	if skip {
+		return m, nil
+	}
+	px, err := s.proxies.Pick(accountName(s.config))
+	if err != nil {
+		return nil, err
+	}
+	m.client = proxy.HTTPClient(px, s.config.Export.ImageTimeoutOrDefault())
+	m.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !zhihuImage(req.URL) {
+			return fmt.Errorf("图片地址跳转到非知乎图片域名: %s", req.URL.Host)
+		}
+		if len(via) >= 10 {
+			return errors.New("图片地址跳转次数过多")
+		}
+		return nil
+	}
+	return m, nil
+}
+
+// localize 返回图片在 Markdown 中的地址，下载失败时保留原地址
+func (m *imageMirror) localize(src string) string {
+	src = originalImage(src)
+	if m.skip {
+		return src
+	}
+	if file, ok := m.files[src]; ok {
+		return file
+	}
+	// 正文中的图片地址来自页面，只下载知乎图床的图片，其他地址保留原样
+	if u, err := url.Parse(src); err != nil || !zhihuImage(u) {
+		logger.FromContext(m.ctx).Debug("不是知乎图片，保留原地址", "url", src)
+		return src
+	}
+
+	file := path.Join(assetsDir, imageFilename(src))
+	if err := m.download(src, filepath.Join(m.dir, filepath.FromSlash(file))); err != nil {
+		m.failed++
+		logger.FromContext(m.ctx).Warn("下载图片失败，保留原地址", "url", src, "error", err)
+		return src
+	}
+	m.files[src] = file
+	return file
+}
+
+func (m *imageMirror) download(src, target string) error {
+	req, err := http.NewRequestWithContext(m.ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Referer", "https://zhuanlan.zhihu.com/")
+	if m.ua != "" {
+		req.Header.Set("User-Agent", m.ua)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("图片地址返回 %d", resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/") {
+		return fmt.Errorf("图片地址返回的不是图片: %q", contentType)
+	}
+	if resp.ContentLength > m.limit {
+		return fmt.Errorf("图片大小 %d 字节超过上限 %d 字节", resp.ContentLength, m.limit)
+	}
+
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	// 多读一个字节判断是否超过上限，不信任 Content-Length
+	n, err := io.Copy(f, io.LimitReader(resp.Body, m.limit+1))
+	if err == nil && n > m.limit {
+		err = fmt.Errorf("图片大小超过上限 %d 字节", m.limit)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(target)
+		return err
+	}
+	return f.Close()
+}
+
+// zhihuImage 判断地址是否为知乎图床（*.zhimg.com）的图片
+func zhihuImage(u *url.URL) bool {
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	return host == "zhimg.com" || strings.HasSuffix(host, ".zhimg.com")
+}
+
+// originalImage 去掉知乎图片地址中的缩放后缀，得到原图地址
+func originalImage(src string) string {
+	if strings.HasPrefix(src, "//") {
+		src = "https:" + src
+	}
+	for _, suffix := range []string{"_r.", "_b."} {
+		if i := strings.LastIndex(src, suffix); i >= 0 {
+			return src[:i] + "." + src[i+len(suffix):]
+		}
+	}
+	return src
+}
+
+// imageFilename 以图片地址的 MD5 作为文件名，不支持的扩展名使用 .jpg
+func imageFilename(src string) string {
+	sum := md5.Sum([]byte(src))
+	ext := ".jpg"
+	if u, err := url.Parse(src); err == nil {
+		switch e := strings.ToLower(path.Ext(u.Path)); e {
+		case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+			ext = e
+		}
+	}
+	return hex.EncodeToString(sum[:]) + ext
+}
+
+// zipDir 将目录打包为 zip，压缩包内的路径相对于目录
+func zipDir(dir, target string) error {
+	f, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	err = filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		dst, err := w.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(dst, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestOriginalImage(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"https://pic1.zhimg.com/v2-abc_r.jpg", "https://pic1.zhimg.com/v2-abc.jpg"},
+		{"https://pic1.zhimg.com/v2-abc_b.png", "https://pic1.zhimg.com/v2-abc.png"},
+		{"//pic2.zhimg.com/v2-abc_r.jpg?source=1", "https://pic2.zhimg.com/v2-abc.jpg?source=1"},
+		{"https://pic3.zhimg.com/v2-abc.jpg", "https://pic3.zhimg.com/v2-abc.jpg"},
+	}
+	for _, tt := range tests {
+		if got := originalImage(tt.src); got != tt.want {
+			t.Errorf("originalImage(%q) = %q，期望 %q", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestImageFilename(t *testing.T) {
+	tests := []struct {
+		src, ext string
+	}{
+		{"https://pic1.zhimg.com/v2-abc.png", ".png"},
+		{"https://pic1.zhimg.com/v2-abc.JPEG?source=1", ".jpeg"},
+		{"https://pic1.zhimg.com/v2-abc.webp", ".webp"},
+		{"https://pic1.zhimg.com/v2-abc.svg", ".jpg"},
+		{"https://pic1.zhimg.com/v2-abc", ".jpg"},
+	}
+	for _, tt := range tests {
+		name := imageFilename(tt.src)
+		if !strings.HasSuffix(name, tt.ext) || len(name) != 32+len(tt.ext) {
+			t.Errorf("imageFilename(%q) = %q，期望 32 位 MD5 加 %s", tt.src, name, tt.ext)
+		}
+	}
+
+	if imageFilename("https://pic1.zhimg.com/a.jpg") == imageFilename("https://pic2.zhimg.com/a.jpg") {
+		t.Error("不同地址的图片文件名相同")
+	}
+	if imageFilename("https://pic1.zhimg.com/a.jpg") != imageFilename("https://pic1.zhimg.com/a.jpg") {
+		t.Error("同一地址的图片文件名不同")
+	}
+}
+
+func TestZhihuImage(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"https://pic1.zhimg.com/v2-abc.jpg", true},
+		{"http://PIC4.ZHIMG.COM/v2-abc.jpg", true},
+		{"https://zhimg.com/a.png", true},
+		{"https://evilzhimg.com/a.png", false},
+		{"https://pic1.zhimg.com.example.com/a.png", false},
+		{"http://127.0.0.1/a.png", false},
+		{"file:///etc/passwd", false},
+		{"ftp://pic1.zhimg.com/a.png", false},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.src)
+		if err != nil {
+			t.Fatalf("解析 %q 失败: %v", tt.src, err)
+		}
+		if got := zhihuImage(u); got != tt.want {
+			t.Errorf("zhihuImage(%q) = %v，期望 %v", tt.src, got, tt.want)
+		}
+	}
+}
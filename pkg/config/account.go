@@ -0,0 +1,6 @@
+package config
+
+// AccountMetricsConfig 账号数据抓取配置
+type AccountMetricsConfig struct {
+	Enabled bool `yaml:"enabled"` // 是否在爬取文章列表后抓取创作中心首页的账号数据
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// 支持的浏览器内核
+const (
+	BrowserChromium = "chromium"
+	BrowserFirefox  = "firefox"
+	BrowserWebKit   = "webkit"
+)
+
+// BrowserConfig 浏览器启动和页面上下文配置
+type BrowserConfig struct {
+	Headless          bool          `yaml:"headless"`          // 无头模式，容器内没有显示器时必须开启
+	Engine            string        `yaml:"engine"`            // 浏览器内核: chromium/firefox/webkit，默认 chromium
+	ExecutablePath    string        `yaml:"executablePath"`    // 浏览器可执行文件路径，留空使用 Playwright 自带的浏览器
+	Args              []string      `yaml:"args"`              // 额外的启动参数
+	SlowMo            time.Duration `yaml:"slowMo"`            // 每个操作之间的延迟，便于观察
+	Viewport          Viewport      `yaml:"viewport"`          // 页面视口大小，留空使用默认值
+	Locale            string        `yaml:"locale"`            // 语言，例如 zh-CN
+	Timezone          string        `yaml:"timezone"`          // 时区，例如 Asia/Shanghai
+	UserAgent         string        `yaml:"userAgent"`         // 留空使用浏览器默认值
+	Timeout           time.Duration `yaml:"timeout"`           // 页面操作的默认超时时间
+	NavigationTimeout time.Duration `yaml:"navigationTimeout"` // 页面跳转的默认超时时间
+	MaxContexts       int           `yaml:"maxContexts"`       // 同时使用的上下文上限，即并发任务数，默认 1
+	MaxUses           int           `yaml:"maxUses"`           // 浏览器分配多少个上下文后重启，0 表示不重启
+}
+
+// Viewport 页面视口大小
+type Viewport struct {
+	Width  int `yaml:"width"`
+	Height int `yaml:"height"`
+}
+
+// EngineName 返回浏览器内核，未配置时为 chromium
+func (c BrowserConfig) EngineName() string {
+	if c.Engine == "" {
+		return BrowserChromium
+	}
+	return c.Engine
+}
+
+// ContextLimit 返回同时使用的上下文上限，未配置时为 1
+func (c BrowserConfig) ContextLimit() int {
+	if c.MaxContexts <= 0 {
+		return 1
+	}
+	return c.MaxContexts
+}
+
+// Validate 校验浏览器配置
+func (c BrowserConfig) Validate() error {
+	switch c.EngineName() {
+	case BrowserChromium, BrowserFirefox, BrowserWebKit:
+	default:
+		return fmt.Errorf("不支持的浏览器内核: %s", c.Engine)
+	}
+
+	if c.ExecutablePath != "" {
+		if _, err := os.Stat(c.ExecutablePath); err != nil {
+			return fmt.Errorf("浏览器可执行文件不可用: %w", err)
+		}
+	}
+
+	if c.Viewport.Width < 0 || c.Viewport.Height < 0 {
+		return fmt.Errorf("视口大小不能为负数")
+	}
+	if (c.Viewport.Width == 0) != (c.Viewport.Height == 0) {
+		return fmt.Errorf("视口的宽和高需要同时设置")
+	}
+
+	if c.Timezone != "" {
+		if _, err := time.LoadLocation(c.Timezone); err != nil {
+			return fmt.Errorf("无效的时区 %s: %w", c.Timezone, err)
+		}
+	}
+
+	if c.SlowMo < 0 || c.Timeout < 0 || c.NavigationTimeout < 0 {
+		return fmt.Errorf("slowMo 和超时时间不能为负数")
+	}
+	if c.MaxContexts < 0 || c.MaxUses < 0 {
+		return fmt.Errorf("maxContexts 和 maxUses 不能为负数")
+	}
+	return nil
+}
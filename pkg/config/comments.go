@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// CommentsConfig 评论爬取配置
+type CommentsConfig struct {
+	Enabled           bool          `yaml:"enabled"`           // 是否在爬取列表后抓取评论数有变化的文章的评论
+	MaxArticlesPerRun int           `yaml:"maxArticlesPerRun"` // 每次任务最多抓取多少篇文章的评论，默认 10
+	RetryInterval     time.Duration `yaml:"retryInterval"`     // 评论数没有变化、已保存评论仍然较少的文章重新抓取的间隔，默认 24h
+}
+
+// Limit 返回每次任务抓取评论的文章数上限
+func (c CommentsConfig) Limit() int {
+	if c.MaxArticlesPerRun <= 0 {
+		return 10
+	}
+	return c.MaxArticlesPerRun
+}
+
+// RetryIntervalOrDefault 返回评论数没有变化的文章重新抓取评论的间隔
+func (c CommentsConfig) RetryIntervalOrDefault() time.Duration {
+	if c.RetryInterval <= 0 {
+		return 24 * time.Hour
+	}
+	return c.RetryInterval
+}
+
+// Validate 校验评论爬取配置
+func (c CommentsConfig) Validate() error {
+	if c.MaxArticlesPerRun < 0 || c.RetryInterval < 0 {
+		return fmt.Errorf("maxArticlesPerRun 和 retryInterval 不能为负数")
+	}
+	return nil
+}
@@ -2,6 +2,9 @@ package config
 
 import (
 	"crawler/pkg/logger"
+	"crawler/pkg/tracing"
+	"fmt"
+	"math"
 	"os"
 	"time"
 
@@ -10,10 +13,23 @@ import (
 
 // Config 总配置结构
 type Config struct {
-	App    AppConfig           `yaml:"app"`
-	Logger logger.LoggerConfig `yaml:"logger"`
-	Server Server              `yaml:"server"`
-	MySQL  MySQLConfig         `yaml:"mysql"`
+	App       AppConfig             `yaml:"app"`
+	Logger    logger.LoggerConfig   `yaml:"logger"`
+	Server    Server                `yaml:"server"`
+	MySQL     MySQLConfig           `yaml:"mysql"`
+	RateLimit RateLimitConfig       `yaml:"rateLimit"`
+	Tracing   tracing.TracingConfig `yaml:"tracing"`
+	Artifacts ArtifactsConfig       `yaml:"artifacts"`
+	Browser   BrowserConfig         `yaml:"browser"`
+	Proxy     ProxyConfig           `yaml:"proxy"`
+	Pacing    PacingConfig          `yaml:"pacing"`
+	Retry     RetryConfig           `yaml:"retry"`
+	Crawl     CrawlConfig           `yaml:"crawl"`
+	Content   ContentConfig         `yaml:"content"`
+	Comments  CommentsConfig        `yaml:"comments"`
+	Account   AccountMetricsConfig  `yaml:"accountMetrics"`
+	Export    ExportConfig          `yaml:"export"`
+	Notify    NotifyConfig          `yaml:"notify"`
 }
 
 // AppConfig 应用配置结构
@@ -36,6 +52,66 @@ type Server struct {
 	AllowedHeaders []string      `yaml:"allowedHeaders"`
 }
 
+// RateLimitConfig 限流配置，requestsPerSecond 和 burst 按认证后的调用方（API 密钥或用户）限流
+type RateLimitConfig struct {
+	Enabled           bool          `yaml:"enabled"`
+	RequestsPerSecond float64       `yaml:"requestsPerSecond"`
+	Burst             int           `yaml:"burst"`
+	IP                IPRateConfig  `yaml:"ip"`
+	IdleTTL           time.Duration `yaml:"idleTTL"`
+	CrawlMinInterval  time.Duration `yaml:"crawlMinInterval"`
+}
+
+// IPRateConfig 认证之前按客户端 IP 的限流，同一 IP 后可能有多个调用方，
+// 未配置时为调用方限流的 ipRateFactor 倍
+type IPRateConfig struct {
+	RequestsPerSecond float64 `yaml:"requestsPerSecond"`
+	Burst             int     `yaml:"burst"`
+}
+
+// ipRateFactor IP 限流未配置时相对调用方限流的倍数
+const ipRateFactor = 5
+
+// WithDefaults 返回补全默认值后的配置，burst 未配置时至少允许一次请求
+func (c RateLimitConfig) WithDefaults() RateLimitConfig {
+	if c.Burst <= 0 {
+		c.Burst = max(1, int(math.Ceil(c.RequestsPerSecond)))
+	}
+	if c.IP.RequestsPerSecond <= 0 {
+		c.IP.RequestsPerSecond = c.RequestsPerSecond * ipRateFactor
+		if c.IP.Burst <= 0 {
+			c.IP.Burst = c.Burst * ipRateFactor
+		}
+	}
+	if c.IP.Burst <= 0 {
+		c.IP.Burst = max(1, int(math.Ceil(c.IP.RequestsPerSecond)))
+	}
+	if c.IdleTTL <= 0 {
+		c.IdleTTL = 10 * time.Minute
+	}
+	return c
+}
+
+// Validate 校验限流配置
+func (c RateLimitConfig) Validate() error {
+	if c.RequestsPerSecond < 0 || c.Burst < 0 || c.IP.RequestsPerSecond < 0 || c.IP.Burst < 0 {
+		return fmt.Errorf("requestsPerSecond 和 burst 不能为负数")
+	}
+	if c.Enabled && c.RequestsPerSecond == 0 {
+		return fmt.Errorf("启用限流时 requestsPerSecond 必须大于 0")
+	}
+	if c.IdleTTL < 0 || c.CrawlMinInterval < 0 {
+		return fmt.Errorf("时间间隔不能为负数")
+	}
+	return nil
+}
+
+// ArtifactsConfig 爬取失败现场保存配置
+type ArtifactsConfig struct {
+	Dir   string `yaml:"dir"`   // 保存目录，每个任务一个子目录
+	Trace bool   `yaml:"trace"` // 是否录制 Playwright trace，失败时保存为 trace.zip
+}
+
 // MySQLConfig MySQL 配置
 type MySQLConfig struct {
 	Host            string        `yaml:"host"`
@@ -67,5 +143,39 @@ func LoadConfig(filepath string) (*Config, error) {
 		return nil, err
 	}
 
+	if err := cfg.RateLimit.Validate(); err != nil {
+		return nil, fmt.Errorf("rateLimit 配置无效: %w", err)
+	}
+	if err := cfg.Browser.Validate(); err != nil {
+		return nil, fmt.Errorf("browser 配置无效: %w", err)
+	}
+	if err := cfg.Proxy.Validate(); err != nil {
+		return nil, fmt.Errorf("proxy 配置无效: %w", err)
+	}
+	if err := cfg.Pacing.Validate(); err != nil {
+		return nil, fmt.Errorf("pacing 配置无效: %w", err)
+	}
+	if err := cfg.Retry.Validate(); err != nil {
+		return nil, fmt.Errorf("retry 配置无效: %w", err)
+	}
+	if err := cfg.Crawl.Validate(); err != nil {
+		return nil, fmt.Errorf("crawl 配置无效: %w", err)
+	}
+	if err := cfg.Content.Validate(); err != nil {
+		return nil, fmt.Errorf("content 配置无效: %w", err)
+	}
+	if err := cfg.Comments.Validate(); err != nil {
+		return nil, fmt.Errorf("comments 配置无效: %w", err)
+	}
+	if err := cfg.Export.Validate(); err != nil {
+		return nil, fmt.Errorf("export 配置无效: %w", err)
+	}
+	if cfg.Server.WriteTimeout > 0 && cfg.Export.Timeout >= cfg.Server.WriteTimeout {
+		return nil, fmt.Errorf("export 配置无效: timeout 必须小于 server.writeTimeout，否则导出结果无法写回")
+	}
+	if err := cfg.Notify.Validate(); err != nil {
+		return nil, fmt.Errorf("notify 配置无效: %w", err)
+	}
+
 	return &cfg, nil
 }
@@ -0,0 +1,25 @@
+package config
+
+import "fmt"
+
+// ContentConfig 文章正文爬取配置
+type ContentConfig struct {
+	Enabled   bool `yaml:"enabled"`   // 是否在爬取列表后逐篇打开文章页抓取正文
+	MaxPerRun int  `yaml:"maxPerRun"` // 每次任务最多抓取多少篇正文，默认 20，0 表示使用默认值
+}
+
+// Limit 返回每次任务抓取正文的上限
+func (c ContentConfig) Limit() int {
+	if c.MaxPerRun <= 0 {
+		return 20
+	}
+	return c.MaxPerRun
+}
+
+// Validate 校验正文爬取配置
+func (c ContentConfig) Validate() error {
+	if c.MaxPerRun < 0 {
+		return fmt.Errorf("maxPerRun 不能为负数")
+	}
+	return nil
+}
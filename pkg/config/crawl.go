@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// 爬取模式
+const (
+	CrawlModeAuto        = "auto"        // 距离上次全量爬取超过 fullRefreshInterval 时全量，否则增量
+	CrawlModeFull        = "full"        // 滚动到列表底部，更新全部文章的统计数据
+	CrawlModeIncremental = "incremental" // 连续遇到已保存的文章后停止
+)
+
+// CrawlConfig 爬取模式配置
+type CrawlConfig struct {
+	Mode                string        `yaml:"mode"`                // 默认爬取模式: auto/full/incremental，默认 auto
+	StopAfterKnown      int           `yaml:"stopAfterKnown"`      // 增量模式下连续遇到多少篇已保存的文章后停止，默认 5
+	FullRefreshInterval time.Duration `yaml:"fullRefreshInterval"` // auto 模式下全量爬取的间隔，默认 24h
+	MaxMissingRatio     *float64      `yaml:"maxMissingRatio"`     // 全量爬取中没有出现的文章超过已保存文章的这个比例（至少 1 篇）时不标记删除，默认 0.1，0 表示不检查
+}
+
+// ModeName 返回默认爬取模式，未配置时为 auto
+func (c CrawlConfig) ModeName() string {
+	if c.Mode == "" {
+		return CrawlModeAuto
+	}
+	return c.Mode
+}
+
+// KnownLimit 返回增量模式的停止阈值
+func (c CrawlConfig) KnownLimit() int {
+	if c.StopAfterKnown <= 0 {
+		return 5
+	}
+	return c.StopAfterKnown
+}
+
+// RefreshInterval 返回全量爬取的间隔
+func (c CrawlConfig) RefreshInterval() time.Duration {
+	if c.FullRefreshInterval <= 0 {
+		return 24 * time.Hour
+	}
+	return c.FullRefreshInterval
+}
+
+// MissingRatio 返回标记删除前允许没有出现的文章比例，未配置时为 0.1，0 表示不检查
+func (c CrawlConfig) MissingRatio() float64 {
+	if c.MaxMissingRatio == nil {
+		return 0.1
+	}
+	return *c.MaxMissingRatio
+}
+
+// ValidCrawlMode 判断爬取模式是否有效
+func ValidCrawlMode(mode string) bool {
+	switch mode {
+	case CrawlModeAuto, CrawlModeFull, CrawlModeIncremental:
+		return true
+	}
+	return false
+}
+
+// Validate 校验爬取模式配置
+func (c CrawlConfig) Validate() error {
+	if !ValidCrawlMode(c.ModeName()) {
+		return fmt.Errorf("不支持的爬取模式: %s", c.Mode)
+	}
+	if c.StopAfterKnown < 0 || c.FullRefreshInterval < 0 {
+		return fmt.Errorf("stopAfterKnown 和 fullRefreshInterval 不能为负数")
+	}
+	if ratio := c.MissingRatio(); ratio < 0 || ratio > 1 {
+		return fmt.Errorf("maxMissingRatio 需要在 0-1 之间")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// ExportConfig Markdown 导出配置
+type ExportConfig struct {
+	Dir           string        `yaml:"dir"`           // 命令行导出的保存目录，每次导出一个子目录和同名 zip，默认 data/exports
+	ImageTimeout  time.Duration `yaml:"imageTimeout"`  // 下载单张图片的超时时间，默认 30s
+	MaxImageBytes int64         `yaml:"maxImageBytes"` // 单张图片的最大字节数，超过时保留原地址，默认 20MB
+	Timeout       time.Duration `yaml:"timeout"`       // 接口导出的最长时间，必须小于 server.writeTimeout，默认为写超时的 5/6
+	Tags          []string      `yaml:"tags"`          // 写入每篇文章 front matter 的默认标签
+}
+
+// OutputDir 返回导出目录
+func (c ExportConfig) OutputDir() string {
+	if c.Dir == "" {
+		return "data/exports"
+	}
+	return c.Dir
+}
+
+// ImageTimeoutOrDefault 返回下载图片的超时时间
+func (c ExportConfig) ImageTimeoutOrDefault() time.Duration {
+	if c.ImageTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return c.ImageTimeout
+}
+
+// MaxImageBytesOrDefault 返回单张图片的最大字节数
+func (c ExportConfig) MaxImageBytesOrDefault() int64 {
+	if c.MaxImageBytes <= 0 {
+		return 20 << 20
+	}
+	return c.MaxImageBytes
+}
+
+// TimeoutOrDefault 返回接口导出的最长时间，未配置时留出发送 zip 的时间，为写超时的 5/6，没有写超时时为 50s
+func (c ExportConfig) TimeoutOrDefault(writeTimeout time.Duration) time.Duration {
+	switch {
+	case c.Timeout > 0:
+		return c.Timeout
+	case writeTimeout > 0:
+		return writeTimeout * 5 / 6
+	}
+	return 50 * time.Second
+}
+
+// Validate 校验导出配置
+func (c ExportConfig) Validate() error {
+	if c.ImageTimeout < 0 {
+		return fmt.Errorf("imageTimeout 不能为负数")
+	}
+	if c.MaxImageBytes < 0 {
+		return fmt.Errorf("maxImageBytes 不能为负数")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout 不能为负数")
+	}
+	return nil
+}
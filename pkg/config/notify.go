@@ -0,0 +1,50 @@
+package config
+
+import (
+	"crawler/pkg/errcode"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// NotifyConfig 任务失败通知配置，失败原因在 reasons 中时向 webhook 发送 JSON 通知
+type NotifyConfig struct {
+	WebhookURL string        `yaml:"webhookURL"` // 接收通知的地址，为空时不发送
+	Reasons    []string      `yaml:"reasons"`    // 需要通知的失败原因，对应错误码名称，默认 blocked 和 not_logged_in
+	Timeout    time.Duration `yaml:"timeout"`    // 发送通知的超时时间，默认 10s
+}
+
+// ReasonsOrDefault 返回需要通知的失败原因
+func (c NotifyConfig) ReasonsOrDefault() []string {
+	if len(c.Reasons) == 0 {
+		return []string{errcode.Blocked.String(), errcode.NotLoggedIn.String()}
+	}
+	return c.Reasons
+}
+
+// TimeoutOrDefault 返回发送通知的超时时间
+func (c NotifyConfig) TimeoutOrDefault() time.Duration {
+	if c.Timeout <= 0 {
+		return 10 * time.Second
+	}
+	return c.Timeout
+}
+
+// Validate 校验通知配置
+func (c NotifyConfig) Validate() error {
+	if c.WebhookURL != "" {
+		u, err := url.Parse(c.WebhookURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("webhookURL 无效: %s", c.WebhookURL)
+		}
+	}
+	for _, reason := range c.Reasons {
+		if _, ok := errcode.Parse(reason); !ok {
+			return fmt.Errorf("未知的失败原因: %s", reason)
+		}
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout 不能为负数")
+	}
+	return nil
+}
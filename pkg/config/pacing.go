@@ -0,0 +1,68 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// PacingConfig 列表滚动节奏配置，延迟在最小值和最大值之间随机
+type PacingConfig struct {
+	MinDelay       time.Duration `yaml:"minDelay"`       // 每轮滚动之间的最小停顿
+	MaxDelay       time.Duration `yaml:"maxDelay"`       // 每轮滚动之间的最大停顿
+	StepMinDelay   time.Duration `yaml:"stepMinDelay"`   // 每次滚轮之间的最小间隔
+	StepMaxDelay   time.Duration `yaml:"stepMaxDelay"`   // 每次滚轮之间的最大间隔
+	ScrollStep     int           `yaml:"scrollStep"`     // 每次滚轮的平均距离（像素），实际距离上下浮动 30%
+	SettleTimeout  time.Duration `yaml:"settleTimeout"`  // 滚动后等待新卡片出现的最长时间
+	NetworkIdle    time.Duration `yaml:"networkIdle"`    // 没有进行中的请求持续多久视为加载完成
+	MaxEmptyRounds int           `yaml:"maxEmptyRounds"` // 连续多少轮没有新文章视为到底
+}
+
+// WithDefaults 返回补全默认值后的配置
+func (c PacingConfig) WithDefaults() PacingConfig {
+	c.MinDelay, c.MaxDelay = delayBounds(c.MinDelay, c.MaxDelay, 800*time.Millisecond, 2*time.Second)
+	c.StepMinDelay, c.StepMaxDelay = delayBounds(c.StepMinDelay, c.StepMaxDelay, 80*time.Millisecond, 250*time.Millisecond)
+	if c.ScrollStep <= 0 {
+		c.ScrollStep = 500
+	}
+	if c.SettleTimeout <= 0 {
+		c.SettleTimeout = 5 * time.Second
+	}
+	if c.NetworkIdle <= 0 {
+		c.NetworkIdle = 500 * time.Millisecond
+	}
+	if c.MaxEmptyRounds <= 0 {
+		c.MaxEmptyRounds = 3
+	}
+	return c
+}
+
+// delayBounds 分别补全延迟的最小值和最大值：只配置了其中一个时，另一个取默认值，
+// 但不会越过已配置的值
+func delayBounds(minDelay, maxDelay, defaultMin, defaultMax time.Duration) (time.Duration, time.Duration) {
+	if minDelay == 0 {
+		minDelay = defaultMin
+		if maxDelay > 0 {
+			minDelay = min(defaultMin, maxDelay)
+		}
+	}
+	if maxDelay == 0 {
+		maxDelay = max(defaultMax, minDelay)
+	}
+	return minDelay, maxDelay
+}
+
+// Validate 校验补全默认值后的滚动节奏配置
+func (c PacingConfig) Validate() error {
+	if c.MinDelay < 0 || c.MaxDelay < 0 || c.StepMinDelay < 0 || c.StepMaxDelay < 0 ||
+		c.SettleTimeout < 0 || c.NetworkIdle < 0 {
+		return fmt.Errorf("延迟和超时时间不能为负数")
+	}
+	c = c.WithDefaults()
+	if c.MaxDelay < c.MinDelay {
+		return fmt.Errorf("maxDelay 不能小于 minDelay")
+	}
+	if c.StepMaxDelay < c.StepMinDelay {
+		return fmt.Errorf("stepMaxDelay 不能小于 stepMinDelay")
+	}
+	return nil
+}
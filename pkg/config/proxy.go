@@ -0,0 +1,57 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ProxyConfig 代理配置，未配置代理时直接访问
+type ProxyConfig struct {
+	Servers     []ProxyServer          `yaml:"servers"`     // 代理列表，一个即单代理，多个时按账号固定分配
+	HealthCheck ProxyHealthCheckConfig `yaml:"healthCheck"` // 健康检查，不可用的代理暂停分配
+}
+
+// ProxyServer 单个代理
+type ProxyServer struct {
+	URL      string `yaml:"url"` // http://host:port、https://host:port 或 socks5://host:port
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// ProxyHealthCheckConfig 代理健康检查配置
+type ProxyHealthCheckConfig struct {
+	URL      string        `yaml:"url"`      // 通过代理访问的检查地址，留空则不检查
+	Interval time.Duration `yaml:"interval"` // 检查间隔，默认 1 分钟
+	Timeout  time.Duration `yaml:"timeout"`  // 单次检查超时时间，默认 10 秒
+}
+
+// Validate 校验代理配置
+func (c ProxyConfig) Validate() error {
+	for i, server := range c.Servers {
+		u, err := url.Parse(server.URL)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("第 %d 个代理地址无效: %s", i+1, server.URL)
+		}
+		switch u.Scheme {
+		case "http", "https":
+		case "socks5":
+			// Playwright 不支持带认证的 SOCKS5 代理
+			if server.Username != "" || u.User != nil {
+				return fmt.Errorf("第 %d 个代理: SOCKS5 代理不支持用户名密码认证", i+1)
+			}
+		default:
+			return fmt.Errorf("第 %d 个代理协议不支持: %s", i+1, u.Scheme)
+		}
+	}
+
+	if c.HealthCheck.URL != "" {
+		if u, err := url.Parse(c.HealthCheck.URL); err != nil || u.Host == "" {
+			return fmt.Errorf("代理健康检查地址无效: %s", c.HealthCheck.URL)
+		}
+	}
+	if c.HealthCheck.Interval < 0 || c.HealthCheck.Timeout < 0 {
+		return fmt.Errorf("代理健康检查间隔和超时时间不能为负数")
+	}
+	return nil
+}
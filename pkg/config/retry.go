@@ -0,0 +1,60 @@
+package config
+
+import (
+	"crawler/pkg/errcode"
+	"fmt"
+	"time"
+)
+
+// RetryConfig 页面跳转、等待元素和数据库写入的重试策略
+type RetryConfig struct {
+	MaxAttempts    int           `yaml:"maxAttempts"`    // 最多尝试次数（含第一次），1 表示不重试
+	InitialBackoff time.Duration `yaml:"initialBackoff"` // 第一次重试前的等待时间
+	MaxBackoff     time.Duration `yaml:"maxBackoff"`     // 等待时间上限
+	Multiplier     float64       `yaml:"multiplier"`     // 每次重试等待时间的倍数
+	Jitter         *float64      `yaml:"jitter"`         // 等待时间随机浮动比例 0-1，不配置时为 0.2，0 表示不浮动
+	RetryOn        []string      `yaml:"retryOn"`        // 可重试的错误类型，对应错误码名称
+}
+
+// WithDefaults 返回补全默认值后的配置
+func (c RetryConfig) WithDefaults() RetryConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 3
+	}
+	if c.InitialBackoff <= 0 {
+		c.InitialBackoff = time.Second
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 30 * time.Second
+	}
+	if c.Multiplier < 1 {
+		c.Multiplier = 2
+	}
+	if c.Jitter == nil {
+		jitter := 0.2
+		c.Jitter = &jitter
+	}
+	if len(c.RetryOn) == 0 {
+		c.RetryOn = []string{"timeout", "selector_missing", "network_error", "db_unavailable"}
+	}
+	return c
+}
+
+// Validate 校验重试配置
+func (c RetryConfig) Validate() error {
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("maxAttempts 不能为负数")
+	}
+	if c.InitialBackoff < 0 || c.MaxBackoff < 0 {
+		return fmt.Errorf("等待时间不能为负数")
+	}
+	if c.Jitter != nil && (*c.Jitter < 0 || *c.Jitter > 1) {
+		return fmt.Errorf("jitter 需要在 0-1 之间")
+	}
+	for _, name := range c.RetryOn {
+		if _, ok := errcode.Parse(name); !ok {
+			return fmt.Errorf("retryOn 中有未知的错误类型: %s", name)
+		}
+	}
+	return nil
+}
@@ -1,9 +1,10 @@
 package cookies
 
 import (
+	"context"
+	"crawler/pkg/errcode"
 	"crawler/pkg/logger"
 	"encoding/json"
-	"fmt"
 	"os"
 
 	"github.com/playwright-community/playwright-go"
@@ -20,29 +21,30 @@ type OriginalCookie struct {
 	Value          string  `json:"value"`
 }
 
-func LoadCookies(context playwright.BrowserContext, cookiesFilePath string) error {
-	logger.Info("开始加载Cookies",
+func LoadCookies(ctx context.Context, browserCtx playwright.BrowserContext, cookiesFilePath string) error {
+	log := logger.FromContext(ctx).WithModule(logger.ModuleCookies)
+	log.Info("开始加载Cookies",
 		"file_path", cookiesFilePath,
 	)
 
 	cookiesData, err := os.ReadFile(cookiesFilePath)
 	if err != nil {
-		logger.Error("读取Cookies文件失败",
+		log.Error("读取Cookies文件失败",
 			"error", err,
 			"file_path", cookiesFilePath,
 		)
-		return fmt.Errorf("读取Cookies文件失败: %w", err)
+		return errcode.Wrap(errcode.PrerequisiteFailed, "读取Cookies文件失败", err)
 	}
 
 	var originalCookies []OriginalCookie
 	if err := json.Unmarshal(cookiesData, &originalCookies); err != nil {
-		logger.Error("解析Cookies数据失败",
+		log.Error("解析Cookies数据失败",
 			"error", err,
 		)
-		return fmt.Errorf("解析Cookies数据失败: %w", err)
+		return errcode.Wrap(errcode.PrerequisiteFailed, "解析Cookies数据失败", err)
 	}
 
-	logger.Info("成功解析Cookies数据",
+	log.Info("成功解析Cookies数据",
 		"count", len(originalCookies),
 	)
 
@@ -76,15 +78,15 @@ func LoadCookies(context playwright.BrowserContext, cookiesFilePath string) erro
 		cookies = append(cookies, cookie)
 	}
 
-	if err := context.AddCookies(cookies); err != nil {
-		logger.Error("添加Cookies到浏览器失败",
+	if err := browserCtx.AddCookies(cookies); err != nil {
+		log.Error("添加Cookies到浏览器失败",
 			"error", err,
 			"cookies_count", len(cookies),
 		)
-		return fmt.Errorf("添加Cookies失败: %w", err)
+		return errcode.Wrap(errcode.BrowserLaunchFailed, "添加Cookies失败", err)
 	}
 
-	logger.Info("Cookies添加成功",
+	log.Info("Cookies添加成功",
 		"count", len(cookies),
 	)
 	return nil
@@ -0,0 +1,132 @@
+package errcode
+
+import (
+	"errors"
+	"time"
+)
+
+// Code 稳定的业务错误码，写入 Response.Code 供调用方判断
+type Code int
+
+// 通用错误 1xxxx
+const (
+	OK                 Code = 0
+	InvalidRequest     Code = 10001
+	Unauthorized       Code = 10002
+	Forbidden          Code = 10003
+	NotFound           Code = 10004
+	Throttled          Code = 10005
+	PrerequisiteFailed Code = 10006
+)
+
+// 爬取错误 2xxxx
+const (
+	NotLoggedIn         Code = 20001
+	Blocked             Code = 20002
+	SelectorMissing     Code = 20003
+	BrowserLaunchFailed Code = 20004
+	Timeout             Code = 20005
+	ProxyUnavailable    Code = 20006
+	NetworkError        Code = 20007
+)
+
+// 存储错误 3xxxx
+const (
+	DBUnavailable Code = 30001
+)
+
+// Internal 未分类的内部错误
+const Internal Code = 50000
+
+var names = map[Code]string{
+	OK:                  "ok",
+	InvalidRequest:      "invalid_request",
+	Unauthorized:        "unauthorized",
+	Forbidden:           "forbidden",
+	NotFound:            "not_found",
+	Throttled:           "throttled",
+	PrerequisiteFailed:  "prerequisite_failed",
+	NotLoggedIn:         "not_logged_in",
+	Blocked:             "blocked",
+	SelectorMissing:     "selector_missing",
+	BrowserLaunchFailed: "browser_launch_failed",
+	Timeout:             "timeout",
+	ProxyUnavailable:    "proxy_unavailable",
+	NetworkError:        "network_error",
+	DBUnavailable:       "db_unavailable",
+	Internal:            "internal",
+}
+
+// String 错误码的稳定名称，如 not_logged_in
+func (c Code) String() string {
+	if name, ok := names[c]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Parse 按稳定名称查找错误码
+func Parse(name string) (Code, bool) {
+	for code, n := range names {
+		if n == name {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// Coder 携带错误码的错误
+type Coder interface {
+	ErrorCode() Code
+}
+
+// RetryAfterer 携带建议重试时间的错误
+type RetryAfterer interface {
+	RetryAfter() time.Duration
+}
+
+// Error 带错误码的错误，errors.Is 按错误码匹配
+type Error struct {
+	Code Code
+	Msg  string
+	Err  error
+}
+
+// New 创建错误，通常用于定义包级别的哨兵错误
+func New(code Code, msg string) *Error {
+	return &Error{Code: code, Msg: msg}
+}
+
+// Wrap 为底层错误附加错误码
+func Wrap(code Code, msg string, err error) *Error {
+	return &Error{Code: code, Msg: msg, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Msg + ": " + e.Err.Error()
+	}
+	return e.Msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+func (e *Error) ErrorCode() Code { return e.Code }
+
+// Is 错误码相同即视为同一类错误
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Of 返回错误链中第一个错误码，没有时返回 Internal
+func Of(err error) Code {
+	if err == nil {
+		return OK
+	}
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.ErrorCode()
+	}
+	return Internal
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Capture 在内存中收集一组日志（JSON Lines），用于按任务保存和实时查看日志
+type Capture struct {
+	mu      sync.Mutex
+	lines   []string
+	closed  bool
+	updated chan struct{} // 有新日志或关闭时被关闭并替换，用于通知等待方
+}
+
+// NewCapture 创建日志收集器
+func NewCapture() *Capture {
+	return &Capture{updated: make(chan struct{})}
+}
+
+// Write 实现 zapcore.WriteSyncer，每次写入对应一条日志
+func (c *Capture) Write(p []byte) (int, error) {
+	line := strings.TrimRight(string(p), "\n")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return len(p), nil
+	}
+	c.lines = append(c.lines, line)
+	c.notifyLocked()
+	return len(p), nil
+}
+
+// Sync 实现 zapcore.WriteSyncer
+func (c *Capture) Sync() error { return nil }
+
+// Close 停止收集，并唤醒所有等待方
+func (c *Capture) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.notifyLocked()
+}
+
+// Since 返回从 offset 开始的日志、用于等待后续日志的通道以及是否已关闭
+func (c *Capture) Since(offset int) ([]string, <-chan struct{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var lines []string
+	if offset < len(c.lines) {
+		lines = append(lines, c.lines[offset:]...)
+	}
+	return lines, c.updated, c.closed
+}
+
+// String 以 JSON Lines 格式返回全部日志
+func (c *Capture) String() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.lines) == 0 {
+		return ""
+	}
+	return strings.Join(c.lines, "\n") + "\n"
+}
+
+func (c *Capture) notifyLocked() {
+	close(c.updated)
+	c.updated = make(chan struct{})
+}
+
+func (l *zapLogger) WithCapture(capture *Capture) Logger {
+	captureCore := zapcore.NewCore(
+		zapcore.NewJSONEncoder(newEncoderConfig()),
+		capture,
+		zapcore.DebugLevel,
+	)
+
+	next := &zapLogger{module: l.module, override: l.override}
+	next.logger = l.logger.Desugar().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			return &levelCore{Core: zapcore.NewTee(lc.Core, captureCore), enabled: lc.enabled}
+		}
+		return zapcore.NewTee(core, captureCore)
+	})).Sugar()
+	return next
+}
@@ -0,0 +1,18 @@
+package logger
+
+import "context"
+
+type contextKey struct{}
+
+// WithContext 将日志实例保存到上下文中
+func WithContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext 获取上下文中的日志实例，未设置时返回全局实例
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return l
+	}
+	return defaultLogger
+}
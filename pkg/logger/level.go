@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 支持单独设置日志级别的模块
+const (
+	ModuleHTTP       = "http"
+	ModuleService    = "service"
+	ModuleScraper    = "scraper"
+	ModuleRepository = "repository"
+	ModuleCookies    = "cookies"
+	ModuleBrowser    = "browser"
+	ModuleProxy      = "proxy"
+)
+
+var knownModules = []string{ModuleHTTP, ModuleService, ModuleScraper, ModuleRepository, ModuleCookies, ModuleBrowser, ModuleProxy}
+
+var (
+	// globalLevel 全局日志级别，可在运行时修改
+	globalLevel = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+
+	levelMu      sync.RWMutex
+	moduleLevels = make(map[string]zapcore.Level) // 模块级别覆盖，未设置的模块跟随全局级别
+)
+
+// LevelInfo 当前日志级别配置
+type LevelInfo struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules"`
+}
+
+// GetLevels 返回全局级别和各模块的覆盖级别
+func GetLevels() LevelInfo {
+	levelMu.RLock()
+	defer levelMu.RUnlock()
+
+	modules := make(map[string]string, len(moduleLevels))
+	for module, level := range moduleLevels {
+		modules[module] = level.String()
+	}
+	return LevelInfo{
+		Level:   globalLevel.Level().String(),
+		Modules: modules,
+	}
+}
+
+// SetLevel 修改日志级别。module 为空时修改全局级别；
+// 指定 module 且 level 为空时清除该模块的覆盖，恢复跟随全局级别。
+func SetLevel(module, level string) error {
+	if module == "" {
+		parsed, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return fmt.Errorf("无效的日志级别: %s", level)
+		}
+		globalLevel.SetLevel(parsed)
+		return nil
+	}
+
+	if !isKnownModule(module) {
+		return fmt.Errorf("未知的日志模块: %s，可选: %v", module, Modules())
+	}
+
+	levelMu.Lock()
+	defer levelMu.Unlock()
+
+	if level == "" {
+		delete(moduleLevels, module)
+		return nil
+	}
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return fmt.Errorf("无效的日志级别: %s", level)
+	}
+	moduleLevels[module] = parsed
+	return nil
+}
+
+// Modules 返回支持单独设置级别的模块
+func Modules() []string {
+	modules := append([]string(nil), knownModules...)
+	sort.Strings(modules)
+	return modules
+}
+
+func isKnownModule(module string) bool {
+	for _, m := range knownModules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
+
+// moduleEnabled 判断模块是否输出指定级别的日志
+func moduleEnabled(module string, level zapcore.Level) bool {
+	if module != "" {
+		levelMu.RLock()
+		moduleLevel, ok := moduleLevels[module]
+		levelMu.RUnlock()
+		if ok {
+			return level >= moduleLevel
+		}
+	}
+	return globalLevel.Enabled(level)
+}
+
+// levelCore 在底层 core 外按模块、任务覆盖和全局级别动态过滤日志。
+// 底层 core 本身不做级别限制，过滤统一在这里完成。
+type levelCore struct {
+	zapcore.Core
+	enabled func(zapcore.Level) bool
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.enabled(level)
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), enabled: c.enabled}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// withLevelFilter 用新的过滤规则替换日志实例的 levelCore
+func (l *zapLogger) withLevelFilter() *zapLogger {
+	module, override := l.module, l.override
+	enabled := func(level zapcore.Level) bool {
+		if override != nil && level >= *override {
+			return true
+		}
+		return moduleEnabled(module, level)
+	}
+
+	l.logger = l.logger.Desugar().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if lc, ok := core.(*levelCore); ok {
+			core = lc.Core
+		}
+		return &levelCore{Core: core, enabled: enabled}
+	})).Sugar()
+	return l
+}
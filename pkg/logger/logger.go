@@ -19,6 +19,12 @@ type Logger interface {
 	Warn(msg string, args ...interface{})
 	Fatal(msg string, args ...interface{})
 	WithFields(fields map[string]interface{}) Logger
+	// WithModule 返回归属指定模块的日志实例，按模块级别过滤
+	WithModule(module string) Logger
+	// WithLevel 返回至少输出指定级别的日志实例，用于临时为单个任务开启调试日志
+	WithLevel(level zapcore.Level) Logger
+	// WithCapture 返回同时将日志写入 capture 的日志实例，之前附加的字段不会写入 capture
+	WithCapture(capture *Capture) Logger
 }
 
 // LoggerConfig 日志配置
@@ -30,14 +36,19 @@ type LoggerConfig struct {
 	MaxBackups int    `yaml:"maxbackups"` // 保留文件个数
 	Compress   bool   `yaml:"compress"`   // 是否压缩
 	Console    bool   `yaml:"console"`    // 是否输出到控制台
+
+	Modules map[string]string `yaml:"modules"` // 模块级别覆盖，如 scraper: debug
 }
 
 type zapLogger struct {
-	logger *zap.SugaredLogger
+	logger   *zap.SugaredLogger
+	module   string
+	override *zapcore.Level
 }
 
 var (
-	defaultLogger Logger
+	// defaultLogger 初始化前不输出日志，单元测试等未初始化的场景调用时不会出现空指针
+	defaultLogger Logger = &zapLogger{logger: zap.NewNop().Sugar()}
 	once          sync.Once
 	initErr       error
 )
@@ -59,10 +70,17 @@ func NewLogger(cfg LoggerConfig) (Logger, error) {
 		return nil, err
 	}
 
+	globalLevel.SetLevel(getLogLevel(cfg.Level))
+	for module, level := range cfg.Modules {
+		if err := SetLevel(module, level); err != nil {
+			return nil, err
+		}
+	}
+
 	cores := buildZapCores(cfg)
 	logger := zap.New(zapcore.NewTee(cores...)).Sugar()
 
-	return &zapLogger{logger: logger}, nil
+	return (&zapLogger{logger: logger}).withLevelFilter(), nil
 }
 
 // 实现 Logger 接口
@@ -77,7 +95,15 @@ func (l *zapLogger) WithFields(fields map[string]interface{}) Logger {
 	for k, v := range fields {
 		args = append(args, k, v)
 	}
-	return &zapLogger{logger: l.logger.With(args...)}
+	return &zapLogger{logger: l.logger.With(args...), module: l.module, override: l.override}
+}
+
+func (l *zapLogger) WithModule(module string) Logger {
+	return (&zapLogger{logger: l.logger, module: module, override: l.override}).withLevelFilter()
+}
+
+func (l *zapLogger) WithLevel(level zapcore.Level) Logger {
+	return (&zapLogger{logger: l.logger, module: l.module, override: &level}).withLevelFilter()
 }
 
 // 全局方法
@@ -99,17 +125,10 @@ func ensureLogDir(filename string) error {
 
 func buildZapCores(cfg LoggerConfig) []zapcore.Core {
 	var cores []zapcore.Core
-	encoderConfig := zapcore.EncoderConfig{
-		TimeKey:        "time",
-		LevelKey:       "level",
-		MessageKey:     "msg",
-		EncodeLevel:    zapcore.LowercaseLevelEncoder,
-		EncodeTime:     zapcore.ISO8601TimeEncoder,
-		EncodeDuration: zapcore.SecondsDurationEncoder,
-		EncodeCaller:   zapcore.ShortCallerEncoder,
-	}
+	encoderConfig := newEncoderConfig()
 
-	level := getLogLevel(cfg.Level)
+	// 底层 core 输出全部级别，实际过滤由 levelCore 按运行时级别完成
+	level := zapcore.DebugLevel
 
 	if cfg.Filename != "" {
 		cores = append(cores, createFileCore(cfg, encoderConfig, level))
@@ -122,6 +141,18 @@ func buildZapCores(cfg LoggerConfig) []zapcore.Core {
 	return cores
 }
 
+func newEncoderConfig() zapcore.EncoderConfig {
+	return zapcore.EncoderConfig{
+		TimeKey:        "time",
+		LevelKey:       "level",
+		MessageKey:     "msg",
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+}
+
 func getLogLevel(levelStr string) zapcore.Level {
 	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
 	if levelStr != "" {
@@ -0,0 +1,141 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "crawler"
+
+// 结果标签取值
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailed  = "failed"
+)
+
+var (
+	// HTTPRequestsTotal HTTP 请求数，按方法、路由、状态码区分
+	HTTPRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP 请求总数",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration HTTP 请求耗时
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP 请求耗时（秒）",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// CrawlRunsTotal 爬取任务次数，按结果区分
+	CrawlRunsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "crawl",
+		Name:      "runs_total",
+		Help:      "爬取任务总数",
+	}, []string{"outcome"})
+
+	// CrawlDuration 爬取任务耗时，按结果区分
+	CrawlDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "crawl",
+		Name:      "duration_seconds",
+		Help:      "爬取任务耗时（秒）",
+		Buckets:   []float64{10, 30, 60, 120, 300, 600, 1200, 1800},
+	}, []string{"outcome"})
+
+	// ArticlesExtracted 每次爬取提取到的文章数
+	ArticlesExtracted = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "crawl",
+		Name:      "articles_extracted",
+		Help:      "每次爬取提取到的文章数",
+		Buckets:   []float64{0, 10, 25, 50, 100, 200, 500, 1000},
+	})
+
+	// ScrollIterationsTotal 列表页滚动次数
+	ScrollIterationsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scraper",
+		Name:      "scroll_iterations_total",
+		Help:      "列表页滚动总次数",
+	})
+
+	// ExtractionErrorsTotal 文章卡片解析失败次数
+	ExtractionErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scraper",
+		Name:      "extraction_errors_total",
+		Help:      "文章卡片解析失败总次数",
+	})
+
+	// BrowserLaunchDuration 浏览器启动耗时，按结果区分
+	BrowserLaunchDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "browser",
+		Name:      "launch_duration_seconds",
+		Help:      "Playwright 与浏览器启动耗时（秒）",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60},
+	}, []string{"outcome"})
+
+	// BrowserContextsActive 正在使用的浏览器上下文数
+	BrowserContextsActive = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "browser",
+		Name:      "contexts_active",
+		Help:      "正在使用的浏览器上下文数",
+	})
+
+	// BrowserRestartsTotal 浏览器重启次数，按原因区分
+	BrowserRestartsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "browser",
+		Name:      "restarts_total",
+		Help:      "浏览器重启总次数",
+	}, []string{"reason"})
+
+	// ProxyHealthy 代理健康状态，1 为可用
+	ProxyHealthy = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "proxy",
+		Name:      "healthy",
+		Help:      "代理是否可用，1 为可用，0 为不可用",
+	}, []string{"proxy"})
+
+	// RetriesTotal 重试次数，按操作区分
+	RetriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "crawl",
+		Name:      "retries_total",
+		Help:      "页面跳转、等待元素、数据库写入的重试总次数",
+	}, []string{"operation"})
+
+	// DBUpsertDuration 文章批量写入耗时，按结果区分
+	DBUpsertDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "upsert_duration_seconds",
+		Help:      "文章批量写入耗时（秒）",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+)
+
+// Handler 暴露 Prometheus 指标
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Outcome 根据错误返回结果标签
+func Outcome(err error) string {
+	if err != nil {
+		return OutcomeFailed
+	}
+	return OutcomeSuccess
+}
@@ -0,0 +1,54 @@
+package mysql
+
+import (
+	"crawler/internal/repository"
+	"crawler/internal/scraper"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// migrate 自动迁移表结构并回填历史数据
+func migrate(db *gorm.DB) error {
+	err := db.AutoMigrate(
+		&repository.Article{},
+		&repository.APIKey{},
+		&repository.CrawlRun{},
+		&repository.ArticleRevision{},
+		&repository.Comment{},
+		&repository.AccountMetricsSnapshot{},
+		&repository.AccountDailyMetric{},
+		&repository.ArticleStatSnapshot{},
+	)
+	if err != nil {
+		return err
+	}
+	if err := backfillPublishedAt(db); err != nil {
+		return fmt.Errorf("回填发布时间失败: %w", err)
+	}
+	return nil
+}
+
+// backfillPublishedAt 为 published_at 为空的文章解析原始发布时间。
+// 相对时间以文章首次保存的时间为基准，无法解析的保持为空，下次启动时会再次尝试
+func backfillPublishedAt(db *gorm.DB) error {
+	db = db.Session(&gorm.Session{Logger: db.Logger.LogMode(logger.Warn)})
+
+	var rows []repository.Article
+	return db.Select("id", "published_time", "created_at").
+		Where("published_at IS NULL AND published_time <> ''").
+		FindInBatches(&rows, 500, func(_ *gorm.DB, _ int) error {
+			for _, row := range rows {
+				publishedAt, err := scraper.ParsePublishedTime(row.PublishedTime, row.CreatedAt)
+				if err != nil {
+					continue
+				}
+				if err := db.Model(&repository.Article{}).Where("id = ?", row.ID).
+					Update("published_at", publishedAt).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
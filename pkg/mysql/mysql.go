@@ -1,9 +1,10 @@
 package mysql
 
 import (
-	"crawler/internal/repository"
 	"crawler/pkg/config"
+	"crawler/pkg/timezone"
 	"fmt"
+	"net/url"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
@@ -12,13 +13,16 @@ import (
 
 // NewDB 创建并初始化数据库连接
 func NewDB(dbConfig config.MySQLConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=Local",
+	// 读写时间统一按 Asia/Shanghai 解释，会话时区与之一致，使 NOW() 和 DATE_FORMAT 的日期与抓取时一致
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=%s&parseTime=True&loc=%s&time_zone=%s",
 		dbConfig.User,
 		dbConfig.Password,
 		dbConfig.Host,
 		dbConfig.Port,
 		dbConfig.Database,
 		dbConfig.Charset,
+		url.QueryEscape(timezone.Shanghai.String()),
+		url.QueryEscape("'"+timezone.MySQLOffset+"'"),
 	)
 
 	// GORM 配置
@@ -33,7 +37,7 @@ func NewDB(dbConfig config.MySQLConfig) (*gorm.DB, error) {
 	}
 
 	// 自动迁移表结构
-	if err := db.AutoMigrate(&repository.Article{}); err != nil {
+	if err := migrate(db); err != nil {
 		return nil, fmt.Errorf("数据库迁移失败: %w", err)
 	}
 
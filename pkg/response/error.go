@@ -0,0 +1,115 @@
+package response
+
+import (
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// httpStatus 错误码对应的 HTTP 状态码
+var httpStatus = map[errcode.Code]int{
+	errcode.InvalidRequest:      http.StatusBadRequest,
+	errcode.Unauthorized:        http.StatusUnauthorized,
+	errcode.Forbidden:           http.StatusForbidden,
+	errcode.NotFound:            http.StatusNotFound,
+	errcode.Throttled:           http.StatusTooManyRequests,
+	errcode.PrerequisiteFailed:  http.StatusPreconditionFailed,
+	errcode.NotLoggedIn:         http.StatusFailedDependency,
+	errcode.Blocked:             http.StatusServiceUnavailable,
+	errcode.SelectorMissing:     http.StatusBadGateway,
+	errcode.BrowserLaunchFailed: http.StatusInternalServerError,
+	errcode.Timeout:             http.StatusGatewayTimeout,
+	errcode.ProxyUnavailable:    http.StatusServiceUnavailable,
+	errcode.NetworkError:        http.StatusBadGateway,
+	errcode.DBUnavailable:       http.StatusServiceUnavailable,
+	errcode.Internal:            http.StatusInternalServerError,
+}
+
+// messages 错误码对应的固定说明，返回给客户端时不包含底层错误
+var messages = map[errcode.Code]string{
+	errcode.InvalidRequest:      "请求参数错误",
+	errcode.Unauthorized:        "未认证",
+	errcode.Forbidden:           "权限不足",
+	errcode.NotFound:            "资源不存在",
+	errcode.Throttled:           "请求过于频繁，请稍后重试",
+	errcode.PrerequisiteFailed:  "前置条件不满足",
+	errcode.NotLoggedIn:         "知乎登录状态失效",
+	errcode.Blocked:             "页面被知乎安全验证拦截",
+	errcode.SelectorMissing:     "页面元素不存在",
+	errcode.BrowserLaunchFailed: "浏览器启动失败",
+	errcode.Timeout:             "处理超时",
+	errcode.ProxyUnavailable:    "没有可用的代理",
+	errcode.NetworkError:        "页面访问网络错误",
+	errcode.DBUnavailable:       "数据库不可用",
+	errcode.Internal:            "服务内部错误",
+}
+
+// clientErrors 由请求本身引起的错误，返回错误自身的说明（不含被包装的底层错误），告诉调用方如何修正请求
+var clientErrors = map[errcode.Code]bool{
+	errcode.InvalidRequest: true,
+	errcode.Unauthorized:   true,
+	errcode.Forbidden:      true,
+	errcode.NotFound:       true,
+	errcode.Throttled:      true,
+}
+
+// HTTPStatus 返回错误对应的 HTTP 状态码
+func HTTPStatus(err error) int {
+	if status, ok := httpStatus[errcode.Of(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Message 返回给客户端的错误说明，不包含数据库、文件系统、浏览器等底层错误
+func Message(err error) string {
+	code := errcode.Of(err)
+	var e *errcode.Error
+	if clientErrors[code] && errors.As(err, &e) && e.Code == code {
+		return e.Msg
+	}
+	if msg, ok := messages[code]; ok {
+		return msg
+	}
+	return messages[errcode.Internal]
+}
+
+// FromError 根据错误码返回对应的 HTTP 状态码、业务错误码和固定的错误说明，错误携带重试时间时设置 Retry-After。
+// 完整的错误只记录在服务端日志中
+func FromError(c *gin.Context, err error) {
+	var retry errcode.RetryAfterer
+	if errors.As(err, &retry) {
+		SetRetryAfter(c, retry.RetryAfter())
+	}
+
+	status, message := HTTPStatus(err), Message(err)
+	if message != err.Error() {
+		log := logger.FromContext(c.Request.Context())
+		if status >= http.StatusInternalServerError {
+			log.Error("请求失败", "code", errcode.Of(err).String(), "status", status, "error", err)
+		} else {
+			log.Warn("请求失败", "code", errcode.Of(err).String(), "status", status, "error", err)
+		}
+	}
+
+	c.JSON(status, Response{
+		Code:    int(errcode.Of(err)),
+		Message: message,
+		TraceID: c.GetString("trace_id"),
+	})
+}
+
+// SetRetryAfter 设置 Retry-After 响应头，单位为秒并向上取整
+func SetRetryAfter(c *gin.Context, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+}
@@ -19,11 +19,3 @@ func Success(c *gin.Context, message string, data interface{}) {
 		TraceID: c.GetString("trace_id"),
 	})
 }
-
-func Error(c *gin.Context, code int, message string) {
-	c.JSON(code, Response{
-		Code:    code,
-		Message: message,
-		TraceID: c.GetString("trace_id"),
-	})
-}
@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"context"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
+	"crawler/pkg/logger"
+	"crawler/pkg/metrics"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// 重试的操作
+const (
+	OpNavigate      = "navigate"
+	OpWaitSelector  = "wait_selector"
+	OpDBUpsert      = "db_upsert"
+	OpDBSyncStatus  = "db_sync_status"
+	OpDBRevision    = "db_revision"
+	OpFetchComments = "fetch_comments"
+	OpDBComments    = "db_comments"
+	OpDBSnapshot    = "db_snapshot"
+)
+
+type counterKey struct{}
+
+// Counter 统计一次任务中各操作的重试次数
+type Counter struct {
+	mu   sync.Mutex
+	byOp map[string]int
+}
+
+func NewCounter() *Counter {
+	return &Counter{byOp: make(map[string]int)}
+}
+
+func (c *Counter) add(op string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.byOp[op]++
+}
+
+// Total 重试总次数，不含每个操作的第一次尝试
+func (c *Counter) Total() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	total := 0
+	for _, n := range c.byOp {
+		total += n
+	}
+	return total
+}
+
+// ByOperation 各操作的重试次数
+func (c *Counter) ByOperation() map[string]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := make(map[string]int, len(c.byOp))
+	for op, n := range c.byOp {
+		result[op] = n
+	}
+	return result
+}
+
+// WithCounter 将重试计数器放入上下文，Do 发生重试时累加
+func WithCounter(ctx context.Context, c *Counter) context.Context {
+	return context.WithValue(ctx, counterKey{}, c)
+}
+
+// Do 执行 fn，返回可重试类型的错误时按指数退避重试，attempt 从 1 开始。
+// 等待重试时上下文被取消，返回同时包装 ctx.Err() 和最后一次错误的错误
+func Do(ctx context.Context, cfg config.RetryConfig, op string, fn func(attempt int) error) error {
+	cfg = cfg.WithDefaults()
+	log := logger.FromContext(ctx)
+
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		if err == nil {
+			return nil
+		}
+		if attempt >= cfg.MaxAttempts || !Retryable(cfg, err) {
+			if attempt > 1 {
+				log.Warn("重试后仍然失败",
+					"operation", op,
+					"attempts", attempt,
+					"error", err,
+				)
+			}
+			return err
+		}
+
+		wait := Backoff(cfg, attempt)
+		log.Warn("操作失败，等待后重试",
+			"operation", op,
+			"attempt", attempt,
+			"max_attempts", cfg.MaxAttempts,
+			"reason", errcode.Of(err).String(),
+			"backoff", wait.String(),
+			"error", err,
+		)
+		metrics.RetriesTotal.WithLabelValues(op).Inc()
+		if c, ok := ctx.Value(counterKey{}).(*Counter); ok {
+			c.add(op)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			// 同时保留取消原因和最后一次错误，调用方可以用 errors.Is 判断是否被取消
+			return fmt.Errorf("%w（最后一次错误: %w）", ctx.Err(), err)
+		}
+	}
+}
+
+// Retryable 判断错误类型是否在可重试列表中
+func Retryable(cfg config.RetryConfig, err error) bool {
+	name := errcode.Of(err).String()
+	for _, class := range cfg.WithDefaults().RetryOn {
+		if class == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Backoff 第 attempt 次失败后的等待时间，按倍数增长并随机浮动
+func Backoff(cfg config.RetryConfig, attempt int) time.Duration {
+	cfg = cfg.WithDefaults()
+	wait := float64(cfg.InitialBackoff) * math.Pow(cfg.Multiplier, float64(attempt-1))
+	if wait > float64(cfg.MaxBackoff) {
+		wait = float64(cfg.MaxBackoff)
+	}
+	jitter := *cfg.Jitter
+	wait *= 1 - jitter + rand.Float64()*2*jitter
+	return time.Duration(wait)
+}
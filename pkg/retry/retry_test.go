@@ -0,0 +1,43 @@
+package retry
+
+import (
+	"context"
+	"crawler/pkg/config"
+	"crawler/pkg/errcode"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDoCanceledWhileWaiting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := config.RetryConfig{MaxAttempts: 5, InitialBackoff: time.Hour}
+	last := errcode.New(errcode.NetworkError, "连接被重置")
+
+	attempts := 0
+	err := Do(ctx, cfg, OpNavigate, func(int) error {
+		attempts++
+		cancel()
+		return last
+	})
+	if attempts != 1 {
+		t.Errorf("尝试次数 = %d，期望 1", attempts)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("取消后返回 %v，期望包含 context.Canceled", err)
+	}
+	if !errors.Is(err, last) || errcode.Of(err) != errcode.NetworkError {
+		t.Errorf("取消后返回 %v，期望保留最后一次错误", err)
+	}
+}
+
+func TestDoStopsOnNonRetryable(t *testing.T) {
+	attempts := 0
+	err := Do(context.Background(), config.RetryConfig{MaxAttempts: 3}, OpNavigate, func(int) error {
+		attempts++
+		return errcode.New(errcode.Blocked, "被拦截")
+	})
+	if attempts != 1 || errcode.Of(err) != errcode.Blocked {
+		t.Errorf("不可重试的错误应直接返回，尝试 %d 次，错误 %v", attempts, err)
+	}
+}
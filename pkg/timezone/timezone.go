@@ -0,0 +1,20 @@
+package timezone
+
+import (
+	"time"
+	_ "time/tzdata" // 容器内可能没有时区数据
+)
+
+// Shanghai 知乎页面展示时间和统计日期使用的时区，抓取、存储和查询统一按此时区划分日期
+var Shanghai = mustLoadLocation("Asia/Shanghai")
+
+// MySQLOffset 数据库会话时区，与 Shanghai 一致（无夏令时），使 NOW() 和 DATE_FORMAT 按同一时区计算
+const MySQLOffset = "+08:00"
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
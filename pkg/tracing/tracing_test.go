@@ -0,0 +1,44 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestStartEnd(t *testing.T) {
+	exporter, restore := InstallMemoryExporter()
+	defer restore()
+
+	ctx, parent := Start(context.Background(), "crawler.run", attribute.String("crawler.mode", "full"))
+	_, child := Start(ctx, "page.goto")
+	End(child, errors.New("连接超时"))
+	End(parent, nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("导出的 Span 数 = %d，期望 2", len(spans))
+	}
+	got, root := spans[0], spans[1]
+	if got.Name != "page.goto" || root.Name != "crawler.run" {
+		t.Fatalf("Span 名称 = %q, %q，期望 page.goto, crawler.run", got.Name, root.Name)
+	}
+	if got.Parent.SpanID() != root.SpanContext.SpanID() {
+		t.Errorf("子 Span 的父 Span 不正确")
+	}
+	if got.Status.Code != codes.Error || got.Status.Description != "连接超时" {
+		t.Errorf("失败的 Span 状态 = %+v，期望 Error", got.Status)
+	}
+	if len(got.Events) != 1 || got.Events[0].Name != "exception" {
+		t.Errorf("失败的 Span 应记录错误事件，得到 %+v", got.Events)
+	}
+	if root.Status.Code != codes.Unset {
+		t.Errorf("成功的 Span 状态 = %+v，期望 Unset", root.Status)
+	}
+	if len(root.Attributes) != 1 || root.Attributes[0] != attribute.String("crawler.mode", "full") {
+		t.Errorf("Span 属性 = %v", root.Attributes)
+	}
+}